@@ -0,0 +1,122 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/go-errors/errors"
+)
+
+// Annotation is one layer of context that was added to an error by Annotate.
+type Annotation struct {
+	// Message is the string-substituted context message.
+	Message string
+
+	// Caller is where Annotate was called.
+	Caller Caller
+}
+
+// annotatedError adds a message to an error without replacing it.
+type annotatedError struct {
+	annotation Annotation
+	cause      error
+}
+
+// Error returns the annotation followed by the message of the error that it
+// annotates.
+func (ae *annotatedError) Error() string {
+	return ae.annotation.Message + ": " + ae.cause.Error()
+}
+
+// Unwrap returns the annotated error.
+func (ae *annotatedError) Unwrap() error {
+	return ae.cause
+}
+
+// Annotate adds context (e.g. "while loading config [x]") to an error as it
+// rises through the layers of an application. The error keeps the stack that
+// was first captured for it (one is captured here if there isn't one yet)
+// and every annotation records where it was added. Returns nil if the error is
+// nil.
+func Annotate(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	ae := &annotatedError{
+		annotation: Annotation{
+			Message: fmt.Sprintf(format, args...),
+			Caller:  getCaller(1),
+		},
+		cause: wrap(err, 1),
+	}
+
+	return wrap(ae, 1)
+}
+
+// Annotations returns the annotations in the error's chain, the most recent
+// first.
+func Annotations(err error) []Annotation {
+	annotations := make([]Annotation, 0)
+
+	walkError(err, func(err error) bool {
+		if ae, ok := err.(*annotatedError); ok == true {
+			annotations = append(annotations, ae.annotation)
+		}
+
+		return false
+	})
+
+	return annotations
+}
+
+// annotatedCause returns the error that the first annotation was added to,
+// without its stack-wrapping.
+func annotatedCause(err error) error {
+	var cause error
+
+	walkError(err, func(err error) bool {
+		if ae, ok := err.(*annotatedError); ok == true {
+			cause = ae.cause
+		}
+
+		return false
+	})
+
+	for {
+		es, ok := cause.(*errors.Error)
+		if ok == false || es.Err == nil {
+			return cause
+		}
+
+		cause = es.Err
+	}
+}
+
+// errorStack renders the error and its stack like ErrorStack() does but, if
+// the error was annotated, also lists the annotations and the original cause.
+func errorStack(es *errors.Error) string {
+	annotations := Annotations(es)
+	if len(annotations) == 0 {
+		return es.ErrorStack()
+	}
+
+	b := new(bytes.Buffer)
+
+	fmt.Fprintf(b, "%s\nAnnotations:\n", es.Error())
+
+	for _, annotation := range annotations {
+		fmt.Fprintf(b, "  %s\n", annotation.Message)
+
+		if annotation.Caller.File != "" {
+			fmt.Fprintf(b, "    at %s (%s)\n", annotation.Caller, annotation.Caller.Function)
+		}
+	}
+
+	cause := annotatedCause(es)
+	fmt.Fprintf(b, "Cause: %T %s\n", cause, cause.Error())
+
+	b.Write(es.Stack())
+
+	return b.String()
+}
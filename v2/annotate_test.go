@@ -0,0 +1,140 @@
+package log
+
+import (
+	e "errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+func loadTestConfig(filepath string) error {
+	err := Errorf("file not found")
+	return Annotate(err, "while loading config [%s]", filepath)
+}
+
+func TestAnnotate(t *testing.T) {
+	original := loadTestConfig("app.yaml")
+
+	err := Annotate(original, "while starting")
+	expectedLine := currentLine() - 1
+
+	if err.Error() != "while starting: while loading config [app.yaml]: file not found" {
+		t.Fatalf("Message not correct: [%s]", err.Error())
+	}
+
+	es, ok := err.(*errors.Error)
+	if ok != true {
+		t.Fatalf("Annotated error should be stack-wrapped: [%T]", err)
+	} else if reflect.DeepEqual(es.Callers(), original.(*errors.Error).Callers()) != true {
+		t.Fatalf("Original stack not kept.")
+	}
+
+	annotations := Annotations(err)
+	if len(annotations) != 2 {
+		t.Fatalf("Annotation count not correct: (%d)", len(annotations))
+	} else if annotations[0].Message != "while starting" || annotations[0].Caller.Line != expectedLine {
+		t.Fatalf("First annotation not correct: %v", annotations[0])
+	} else if annotations[1].Message != "while loading config [app.yaml]" || annotations[1].Caller.Function != "loadTestConfig" {
+		t.Fatalf("Second annotation not correct: %v", annotations[1])
+	} else if path.Base(annotations[1].Caller.File) != "annotate_test.go" {
+		t.Fatalf("Annotation file not correct: [%s]", annotations[1].Caller.File)
+	}
+
+	if Is(err, original) != true {
+		t.Fatalf("Annotated error not found in the chain.")
+	}
+}
+
+func TestAnnotate__Nil(t *testing.T) {
+	if err := Annotate(nil, "while starting"); err != nil {
+		t.Fatalf("Nil error should not be annotated: [%v]", err)
+	}
+}
+
+func TestAnnotate__Unwrapped(t *testing.T) {
+	err := Annotate(os.ErrNotExist, "while reading [%s]", "a.txt")
+
+	if err.Error() != "while reading [a.txt]: file does not exist" {
+		t.Fatalf("Message not correct: [%s]", err.Error())
+	} else if Is(err, os.ErrNotExist) != true {
+		t.Fatalf("Original error not found in the chain.")
+	} else if strings.Contains(err.(*errors.Error).ErrorStack(), "TestAnnotate__Unwrapped") != true {
+		t.Fatalf("Stack not captured at the first annotation.")
+	}
+
+	var pathErr *os.PathError
+	if As(Annotate(&os.PathError{Op: "open", Path: "a.txt", Err: os.ErrNotExist}, "while reading"), &pathErr) != true {
+		t.Fatalf("Original error not found by As().")
+	}
+}
+
+func TestAnnotate__MixedChain(t *testing.T) {
+	inner := Errorf("inner error")
+	err := Annotate(fmt.Errorf("middle: %w", Annotate(inner, "first")), "second")
+
+	if err.Error() != "second: middle: first: inner error" {
+		t.Fatalf("Message not correct: [%s]", err.Error())
+	} else if reflect.DeepEqual(err.(*errors.Error).Callers(), inner.Callers()) != true {
+		t.Fatalf("Original stack not kept.")
+	} else if len(Annotations(err)) != 2 {
+		t.Fatalf("Annotations not found through a stdlib wrapper: %v", Annotations(err))
+	}
+}
+
+func TestErrorStack__Annotated(t *testing.T) {
+	err := Annotate(loadTestConfig("app.yaml"), "while starting")
+	expectedLine := currentLine() - 1
+
+	stack := errorStack(err.(*errors.Error))
+
+	expectedHead := fmt.Sprintf(`while starting: while loading config [app.yaml]: file not found
+Annotations:
+  while starting
+    at annotate_test.go:%d (TestErrorStack__Annotated)
+  while loading config [app.yaml]
+    at annotate_test.go:%d (loadTestConfig)
+Cause: *errors.errorString file not found
+`, expectedLine, Annotations(err)[1].Caller.Line)
+
+	if strings.HasPrefix(stack, expectedHead) != true {
+		t.Fatalf("Stack not rendered correctly:\n%s", stack)
+	} else if strings.Contains(stack, "loadTestConfig") != true {
+		t.Fatalf("Original stack not rendered:\n%s", stack)
+	}
+
+	plain := Errorf("plain error")
+	if errorStack(plain) != plain.ErrorStack() {
+		t.Fatalf("Unannotated stack should be rendered as-is.")
+	}
+}
+
+func TestLogger_Errorf__Annotated(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("annotateTest", "test")
+
+	err := Annotate(e.New("an error happened"), "while testing")
+	l.Errorf(nil, err, "Error message")
+
+	if strings.Contains(tla.lastMessage, "Annotations:\n  while testing\n") != true {
+		t.Fatalf("Annotations not rendered in the message:\n%s", tla.lastMessage)
+	} else if strings.Contains(tla.lastContext.ErrorStack(), "Cause: *errors.errorString an error happened\n") != true {
+		t.Fatalf("Cause not rendered in the error stack:\n%s", tla.lastContext.ErrorStack())
+	}
+}
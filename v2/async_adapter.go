@@ -0,0 +1,281 @@
+package log
+
+import (
+	e "errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy determines what AsyncLogAdapter does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the logging call until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest discards the message being logged.
+	OverflowDropNewest OverflowPolicy = iota
+
+	// OverflowDropOldest discards the oldest queued message to make room for
+	// the message being logged.
+	OverflowDropOldest OverflowPolicy = iota
+)
+
+var (
+	// ErrAdapterClosed is returned when flushing an adapter that has been
+	// closed.
+	ErrAdapterClosed = e.New("adapter closed")
+)
+
+type asyncEntry struct {
+	lm      logMethod
+	lc      *LogContext
+	message string
+
+	// flushed, if not nil, is closed once all prior entries have been written.
+	flushed chan struct{}
+}
+
+// AsyncLogAdapter decorates another adapter so that messages are queued and
+// written by a background goroutine rather than by the logging goroutine.
+// Since errors from the wrapped adapter can not be returned to the caller,
+// they are passed to the error-handler (which prints them to STDERR by
+// default).
+type AsyncLogAdapter struct {
+	la     LogAdapter
+	policy OverflowPolicy
+	queue  chan asyncEntry
+
+	errorHandler func(err error)
+
+	dropped         uint64
+	droppedReported uint64
+
+	closed  bool
+	closeM  sync.RWMutex
+	drained chan struct{}
+}
+
+// NewAsyncLogAdapter starts a background writer for the given adapter and
+// returns the decorated adapter. queueSize is the number of messages that can
+// be pending before the overflow policy applies.
+func NewAsyncLogAdapter(la LogAdapter, queueSize int, policy OverflowPolicy) *AsyncLogAdapter {
+	if la == nil {
+		Panic(e.New("adapter is nil"))
+	}
+
+	if queueSize < 1 {
+		Panicf("queue-size must be at least one: (%d)", queueSize)
+	}
+
+	ala := &AsyncLogAdapter{
+		la:           la,
+		policy:       policy,
+		queue:        make(chan asyncEntry, queueSize),
+		errorHandler: printAsyncError,
+		drained:      make(chan struct{}),
+	}
+
+	go ala.run()
+
+	return ala
+}
+
+// SetErrorHandler sets the function that receives errors returned by the
+// wrapped adapter. Must be called before logging.
+func (ala *AsyncLogAdapter) SetErrorHandler(errorHandler func(err error)) {
+	ala.errorHandler = errorHandler
+}
+
+// Adapter returns the wrapped adapter.
+func (ala *AsyncLogAdapter) Adapter() LogAdapter {
+	return ala.la
+}
+
+// Dropped returns the total number of messages discarded due to overflow or
+// because they were logged after Close.
+func (ala *AsyncLogAdapter) Dropped() uint64 {
+	return atomic.LoadUint64(&ala.dropped)
+}
+
+// Tracef queues a trace message.
+func (ala *AsyncLogAdapter) Tracef(lc *LogContext, message *string) error {
+	return ala.enqueue(adapterMethod(ala.la, LevelTrace), lc, message)
+}
+
+// Debugf queues a debugging message.
+func (ala *AsyncLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return ala.enqueue(ala.la.Debugf, lc, message)
+}
+
+// Infof queues an info message.
+func (ala *AsyncLogAdapter) Infof(lc *LogContext, message *string) error {
+	return ala.enqueue(ala.la.Infof, lc, message)
+}
+
+// Warningf queues a warning message.
+func (ala *AsyncLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return ala.enqueue(ala.la.Warningf, lc, message)
+}
+
+// Errorf queues an error message.
+func (ala *AsyncLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return ala.enqueue(ala.la.Errorf, lc, message)
+}
+
+// Criticalf queues a critical message.
+func (ala *AsyncLogAdapter) Criticalf(lc *LogContext, message *string) error {
+	return ala.enqueue(adapterMethod(ala.la, LevelCritical), lc, message)
+}
+
+// Flush blocks until every message queued before the call has been written.
+func (ala *AsyncLogAdapter) Flush() error {
+	ala.closeM.RLock()
+	defer ala.closeM.RUnlock()
+
+	if ala.closed == true {
+		return ErrAdapterClosed
+	}
+
+	flushed := make(chan struct{})
+	ala.queue <- asyncEntry{flushed: flushed}
+
+	<-flushed
+
+	return nil
+}
+
+// Close stops accepting messages, writes everything still queued, and stops
+// the background writer. Messages logged afterward are dropped (see Dropped).
+// It is safe to call more than once.
+func (ala *AsyncLogAdapter) Close() error {
+	ala.closeM.Lock()
+
+	if ala.closed == false {
+		ala.closed = true
+		close(ala.queue)
+	}
+
+	ala.closeM.Unlock()
+
+	<-ala.drained
+
+	return nil
+}
+
+func (ala *AsyncLogAdapter) enqueue(lm logMethod, lc *LogContext, message *string) error {
+	// A read-lock is enough to keep Close() from closing the channel while we
+	// send on it.
+	ala.closeM.RLock()
+	defer ala.closeM.RUnlock()
+
+	// Don't fail late messages (e.g. from goroutines that are still running
+	// during shutdown) since the logger would panic with the error.
+	if ala.closed == true {
+		atomic.AddUint64(&ala.dropped, 1)
+		return nil
+	}
+
+	ae := asyncEntry{
+		lm:      lm,
+		lc:      lc,
+		message: *message,
+	}
+
+	switch ala.policy {
+	case OverflowBlock:
+		ala.queue <- ae
+	case OverflowDropNewest:
+		select {
+		case ala.queue <- ae:
+		default:
+			atomic.AddUint64(&ala.dropped, 1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case ala.queue <- ae:
+				return nil
+			default:
+			}
+
+			select {
+			case oldest := <-ala.queue:
+				if oldest.flushed != nil {
+					// Never drop a flush request. Put it back and discard the
+					// new message instead.
+					ala.queue <- oldest
+					atomic.AddUint64(&ala.dropped, 1)
+
+					return nil
+				}
+
+				atomic.AddUint64(&ala.dropped, 1)
+			default:
+			}
+		}
+	default:
+		return fmt.Errorf("overflow policy not valid: (%d)", ala.policy)
+	}
+
+	return nil
+}
+
+func (ala *AsyncLogAdapter) run() {
+	defer close(ala.drained)
+
+	for ae := range ala.queue {
+		if ae.flushed != nil {
+			ala.reportDropped()
+			close(ae.flushed)
+
+			continue
+		}
+
+		ala.reportDropped()
+
+		if err := ae.lm(ae.lc, &ae.message); err != nil {
+			ala.errorHandler(err)
+		}
+	}
+
+	ala.reportDropped()
+}
+
+// reportDropped writes a warning to the wrapped adapter if messages were
+// dropped since the last report.
+func (ala *AsyncLogAdapter) reportDropped() {
+	dropped := atomic.LoadUint64(&ala.dropped)
+	if dropped == ala.droppedReported {
+		return
+	}
+
+	count := dropped - ala.droppedReported
+	ala.droppedReported = dropped
+
+	message := fmt.Sprintf("async log-adapter dropped (%d) messages due to a full queue", count)
+
+	r := loadRegistry()
+
+	lc := &LogContext{
+		level:   LevelWarning,
+		message: message,
+		time: Timestamp{
+			Time:  r.clock.Now(),
+			start: r.clockStart,
+		},
+		fields: Fields{
+			NewField("dropped", count),
+		},
+	}
+
+	if err := ala.la.Warningf(lc, &message); err != nil {
+		ala.errorHandler(err)
+	}
+}
+
+func printAsyncError(err error) {
+	fmt.Fprintf(os.Stderr, "async log-adapter could not write message: %s\n", err)
+}
@@ -0,0 +1,285 @@
+package log
+
+import (
+	e "errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// A test logging-adapter that records messages and optionally blocks until
+// released.
+type gatedLogAdapter struct {
+	gate     chan struct{}
+	messages []string
+	m        sync.Mutex
+}
+
+func newGatedLogAdapter(isBlocked bool) *gatedLogAdapter {
+	gla := &gatedLogAdapter{
+		gate: make(chan struct{}),
+	}
+
+	if isBlocked == false {
+		close(gla.gate)
+	}
+
+	return gla
+}
+
+func (gla *gatedLogAdapter) record(message *string) error {
+	<-gla.gate
+
+	gla.m.Lock()
+	defer gla.m.Unlock()
+
+	gla.messages = append(gla.messages, *message)
+
+	return nil
+}
+
+func (gla *gatedLogAdapter) Messages() []string {
+	gla.m.Lock()
+	defer gla.m.Unlock()
+
+	return gla.messages
+}
+
+func (gla *gatedLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return gla.record(message)
+}
+
+func (gla *gatedLogAdapter) Infof(lc *LogContext, message *string) error {
+	return gla.record(message)
+}
+
+func (gla *gatedLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return gla.record(message)
+}
+
+func (gla *gatedLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return gla.record(message)
+}
+
+func TestAsyncLogAdapter(t *testing.T) {
+	gla := newGatedLogAdapter(false)
+	ala := NewAsyncLogAdapter(gla, 10, OverflowBlock)
+
+	for _, message := range []string{"aa", "bb", "cc"} {
+		err := ala.Infof(&LogContext{}, &message)
+		PanicIf(err)
+	}
+
+	err := ala.Flush()
+	PanicIf(err)
+
+	messages := gla.Messages()
+	if len(messages) != 3 || messages[0] != "aa" || messages[2] != "cc" {
+		t.Fatalf("Messages not correct: %v", messages)
+	}
+
+	err = ala.Close()
+	PanicIf(err)
+}
+
+func TestAsyncLogAdapter__ThroughLogger(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	gla := newGatedLogAdapter(false)
+	ala := NewAsyncLogAdapter(gla, 10, OverflowBlock)
+	AddAdapter("async", ala)
+
+	l := NewLoggerWithAdapterName("asyncTest", "async")
+	l.Infof(nil, "Info message")
+
+	err := ala.Close()
+	PanicIf(err)
+
+	messages := gla.Messages()
+	if len(messages) != 1 || messages[0] != "asyncTest: [INFO]  Info message" {
+		t.Fatalf("Messages not correct: %v", messages)
+	}
+}
+
+func TestAsyncLogAdapter__DropNewest(t *testing.T) {
+	gla := newGatedLogAdapter(true)
+	ala := NewAsyncLogAdapter(gla, 2, OverflowDropNewest)
+
+	// The writer takes the first message and then blocks on the gate, so two
+	// more fit in the queue and the rest are dropped.
+	for _, message := range []string{"aa", "bb", "cc", "dd", "ee"} {
+		err := ala.Infof(&LogContext{}, &message)
+		PanicIf(err)
+
+		waitForAsyncQueueToSettle(ala, message)
+	}
+
+	close(gla.gate)
+
+	err := ala.Close()
+	PanicIf(err)
+
+	if ala.Dropped() != 2 {
+		t.Fatalf("Dropped count not correct: (%d)", ala.Dropped())
+	}
+
+	messages := gla.Messages()
+	if len(messages) != 4 {
+		t.Fatalf("Expected three messages and one report: %v", messages)
+	} else if messages[0] != "aa" || messages[2] != "bb" || messages[3] != "cc" {
+		t.Fatalf("Wrong messages were kept: %v", messages)
+	} else if messages[1] != "async log-adapter dropped (2) messages due to a full queue" {
+		t.Fatalf("Dropped report not correct: [%s]", messages[1])
+	}
+}
+
+func TestAsyncLogAdapter__DropOldest(t *testing.T) {
+	gla := newGatedLogAdapter(true)
+	ala := NewAsyncLogAdapter(gla, 2, OverflowDropOldest)
+
+	for _, message := range []string{"aa", "bb", "cc", "dd", "ee"} {
+		err := ala.Infof(&LogContext{}, &message)
+		PanicIf(err)
+
+		waitForAsyncQueueToSettle(ala, message)
+	}
+
+	close(gla.gate)
+
+	err := ala.Close()
+	PanicIf(err)
+
+	if ala.Dropped() != 2 {
+		t.Fatalf("Dropped count not correct: (%d)", ala.Dropped())
+	}
+
+	messages := gla.Messages()
+	if len(messages) != 4 {
+		t.Fatalf("Expected three messages and one report: %v", messages)
+	} else if messages[0] != "aa" || messages[2] != "dd" || messages[3] != "ee" {
+		t.Fatalf("Wrong messages were kept: %v", messages)
+	}
+}
+
+func TestAsyncLogAdapter_Close(t *testing.T) {
+	gla := newGatedLogAdapter(false)
+	ala := NewAsyncLogAdapter(gla, 10, OverflowBlock)
+
+	err := ala.Close()
+	PanicIf(err)
+
+	// Closing again is a no-op.
+	err = ala.Close()
+	PanicIf(err)
+
+	message := "message"
+	err = ala.Infof(&LogContext{}, &message)
+	if err != nil {
+		t.Fatalf("Late message should be dropped rather than fail: [%v]", err)
+	} else if ala.Dropped() != 1 {
+		t.Fatalf("Late message not counted as dropped: (%d)", ala.Dropped())
+	} else if len(gla.Messages()) != 0 {
+		t.Fatalf("Late message should not be written: %v", gla.Messages())
+	}
+
+	err = ala.Flush()
+	if err != ErrAdapterClosed {
+		t.Fatalf("Expected closed error on flush: [%v]", err)
+	}
+}
+
+func TestAsyncLogAdapter_Close__ThroughLogger(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	gla := newGatedLogAdapter(false)
+	ala := NewAsyncLogAdapter(gla, 10, OverflowBlock)
+	AddAdapter("async", ala)
+
+	l := NewLoggerWithAdapterName("asyncTest", "async")
+	l.Infof(nil, "Info message")
+
+	err := ala.Close()
+	PanicIf(err)
+
+	// This would panic if the adapter returned an error.
+	l.Infof(nil, "Late message")
+
+	if messages := gla.Messages(); len(messages) != 1 {
+		t.Fatalf("Only the message before closing should be written: %v", messages)
+	} else if ala.Dropped() != 1 {
+		t.Fatalf("Late message not counted as dropped: (%d)", ala.Dropped())
+	}
+}
+
+func TestAsyncLogAdapter__ErrorHandler(t *testing.T) {
+	tla := &failingLogAdapter{}
+	ala := NewAsyncLogAdapter(tla, 10, OverflowBlock)
+
+	var handled []error
+	ala.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	message := "message"
+	err := ala.Errorf(&LogContext{}, &message)
+	PanicIf(err)
+
+	err = ala.Close()
+	PanicIf(err)
+
+	if len(handled) != 1 || handled[0] != errTestAdapterFailure {
+		t.Fatalf("Adapter error not handled: %v", handled)
+	}
+}
+
+var (
+	errTestAdapterFailure = e.New("adapter failure")
+)
+
+// A test logging-adapter that always fails.
+type failingLogAdapter struct {
+}
+
+func (fla *failingLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return errTestAdapterFailure
+}
+
+func (fla *failingLogAdapter) Infof(lc *LogContext, message *string) error {
+	return errTestAdapterFailure
+}
+
+func (fla *failingLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return errTestAdapterFailure
+}
+
+func (fla *failingLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return errTestAdapterFailure
+}
+
+// waitForAsyncQueueToSettle waits until the first message has been picked up
+// by the writer so that the queue contents are deterministic.
+func waitForAsyncQueueToSettle(ala *AsyncLogAdapter, message string) {
+	if message != "aa" {
+		return
+	}
+
+	for len(ala.queue) != 0 {
+		runtime.Gosched()
+	}
+}
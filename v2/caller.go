@@ -0,0 +1,103 @@
+package log
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Caller describes the call-site of a logging call.
+type Caller struct {
+	// File is the full path of the source file.
+	File string
+
+	// Line is the line-number in the source file.
+	Line int
+
+	// Function is the name of the function (or method, e.g. "(*Type).Method")
+	// without the package.
+	Function string
+
+	// Package is the import-path of the package.
+	Package string
+}
+
+// String returns the caller as "file.go:line" or an empty string if the
+// caller was not captured.
+func (c Caller) String() string {
+	if c.File == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", path.Base(c.File), c.Line)
+}
+
+// SetCallerCapture sets whether the call-site is recorded for every logged
+// message. This is disabled by default because it costs a stack lookup for
+// every message that isn't filtered.
+func SetCallerCapture(isEnabled bool) {
+	updateRegistry(func(r *registry) {
+		r.captureCaller = isEnabled
+	})
+}
+
+// IsCallerCaptureEnabled returns whether the call-site is being recorded.
+func IsCallerCaptureEnabled() bool {
+	return loadRegistry().captureCaller
+}
+
+// getCaller returns the caller the given number of frames above the function
+// that calls getCaller.
+func getCaller(skip int) Caller {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if ok == false {
+		return Caller{}
+	}
+
+	c := Caller{
+		File: file,
+		Line: line,
+	}
+
+	if f := runtime.FuncForPC(pc); f != nil {
+		c.Package, c.Function = splitFunctionName(f.Name())
+	}
+
+	return c
+}
+
+// getCallerFromPC returns the caller at the given program-counter (e.g. as
+// recorded by runtime.Callers).
+func getCallerFromPC(pc uintptr) Caller {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+
+	c := Caller{
+		File: frame.File,
+		Line: frame.Line,
+	}
+
+	if frame.Function != "" {
+		c.Package, c.Function = splitFunctionName(frame.Function)
+	}
+
+	return c
+}
+
+// splitFunctionName splits a fully-qualified function name (e.g.
+// "github.com/a/b.(*T).Method") into its package and function.
+func splitFunctionName(name string) (pkg, function string) {
+	// Dots in the last component of the package path are escaped, so the
+	// first dot after the last slash ends the package.
+	lastSlash := strings.LastIndex(name, "/")
+
+	i := strings.Index(name[lastSlash+1:], ".")
+	if i == -1 {
+		return "", name
+	}
+
+	i += lastSlash + 1
+
+	return name[:i], name[i+1:]
+}
@@ -0,0 +1,197 @@
+package log
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// currentLine returns the line-number of the caller.
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func setupCallerTest() (tla *testLogAdapter, l *Logger) {
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	SetCallerCapture(true)
+
+	ClearAdapters()
+
+	tla = newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l = NewLoggerWithAdapterName("callerTest", "test")
+
+	return tla, l
+}
+
+func checkCaller(t *testing.T, lc *LogContext, expectedLine int) {
+	caller := lc.Caller()
+
+	if path.Base(caller.File) != "caller_test.go" {
+		t.Fatalf("Caller file not correct: [%s]", caller.File)
+	} else if caller.Line != expectedLine {
+		t.Fatalf("Caller line not correct: (%d) != (%d)", caller.Line, expectedLine)
+	} else if caller.Package != "github.com/dsoprea/go-logging/v2" {
+		t.Fatalf("Caller package not correct: [%s]", caller.Package)
+	} else if strings.HasPrefix(caller.Function, "TestCaller") != true {
+		t.Fatalf("Caller function not correct: [%s]", caller.Function)
+	}
+}
+
+func TestCaller__Disabled(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+	SetCallerCapture(false)
+
+	l.Infof(nil, "Info message")
+
+	if caller := tla.lastContext.Caller(); caller != (Caller{}) {
+		t.Fatalf("Caller should not be captured: %v", caller)
+	} else if caller.String() != "" {
+		t.Fatalf("Empty caller should render as empty: [%s]", caller.String())
+	}
+}
+
+func TestCaller__Methods(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+	err := e.New("an error happened")
+
+	l.Debugf(nil, "Debug message")
+	checkCaller(t, tla.lastContext, currentLine()-1)
+
+	l.InfoFieldsf(nil, Fields{NewField("a", 1)}, "Info message")
+	checkCaller(t, tla.lastContext, currentLine()-1)
+
+	l.Logf(nil, LevelWarning, "Warning message")
+	checkCaller(t, tla.lastContext, currentLine()-1)
+
+	l.Errorf(nil, err, "Error message")
+	checkCaller(t, tla.lastContext, currentLine()-1)
+
+	l.ErrorIff(nil, err, "Error message")
+	checkCaller(t, tla.lastContext, currentLine()-1)
+
+	l.CriticalFieldsf(nil, err, nil, "Critical message")
+	checkCaller(t, tla.lastContext, currentLine()-1)
+}
+
+func TestCaller__Panics(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+	err := e.New("an error happened")
+
+	var expectedLine int
+
+	func() {
+		defer func() {
+			if state := recover(); state == nil {
+				t.Fatalf("Expected panic.")
+			}
+		}()
+
+		expectedLine = currentLine() + 1
+		l.Panicf(nil, err, "Panic message")
+	}()
+
+	checkCaller(t, tla.lastContext, expectedLine)
+
+	func() {
+		defer func() {
+			if state := recover(); state == nil {
+				t.Fatalf("Expected panic.")
+			}
+		}()
+
+		expectedLine = currentLine() + 1
+		l.PanicIff(nil, err, "Panic message")
+	}()
+
+	checkCaller(t, tla.lastContext, expectedLine)
+}
+
+func TestCaller__Template(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameDebug)
+	scp.SetFormat("{{.Caller}} {{.Caller.Function}}: {{.Message}}")
+
+	LoadConfiguration(scp)
+	SetCallerCapture(true)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("callerTest", "test")
+
+	l.Infof(nil, "Info message")
+	expected := fmt.Sprintf("caller_test.go:%d TestCaller__Template: Info message", currentLine()-1)
+
+	if tla.lastMessage != expected {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+}
+
+func TestCaller__Logfmt(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	setupCallerTest()
+
+	b := new(bytes.Buffer)
+	AddAdapter("logfmt", NewLogfmtLogAdapter(b))
+
+	l := NewLoggerWithAdapterName("callerTest", "logfmt")
+
+	l.Infof(nil, "Info message")
+	expected := fmt.Sprintf("level=info noun=callerTest msg=\"Info message\" bypass=false caller=caller_test.go:%d\n", currentLine()-1)
+
+	if b.String() != expected {
+		t.Fatalf("Line not correct: [%s]", b.String())
+	}
+}
+
+func TestSplitFunctionName(t *testing.T) {
+	cases := map[string][2]string{
+		"github.com/a/b.(*T).Method": {"github.com/a/b", "(*T).Method"},
+		"github.com/a/b.Func.func1":  {"github.com/a/b", "Func.func1"},
+		"main.main":                  {"main", "main"},
+		"gopkg.in/yaml%2ev2.Func":    {"gopkg.in/yaml%2ev2", "Func"},
+		"nodots":                     {"", "nodots"},
+	}
+
+	for name, expected := range cases {
+		pkg, function := splitFunctionName(name)
+		if pkg != expected[0] || function != expected[1] {
+			t.Fatalf("Function name [%s] not split correctly: [%s] [%s]", name, pkg, function)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// CapturedEntry is a message recorded by CaptureLogAdapter.
+type CapturedEntry struct {
+	Level     LogLevel
+	LevelName LogLevelName
+	Noun      string
+
+	// Message is the string-substituted message before it was passed through
+	// the format template (see LogContext.Message).
+	Message string
+
+	// Formatted is the message as it was given to the adapter.
+	Formatted string
+
+	Fields Fields
+	Error  error
+	Caller Caller
+	Time   Timestamp
+}
+
+// CaptureLogAdapter records every message in memory so that tests can make
+// assertions about what was logged.
+type CaptureLogAdapter struct {
+	entries []CapturedEntry
+	m       sync.Mutex
+}
+
+// NewCaptureLogAdapter returns a new CaptureLogAdapter.
+func NewCaptureLogAdapter() *CaptureLogAdapter {
+	return new(CaptureLogAdapter)
+}
+
+// Tracef records a trace message.
+func (cla *CaptureLogAdapter) Tracef(lc *LogContext, message *string) error {
+	return cla.record(lc, LevelTrace, message)
+}
+
+// Debugf records a debugging message.
+func (cla *CaptureLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return cla.record(lc, LevelDebug, message)
+}
+
+// Infof records an info message.
+func (cla *CaptureLogAdapter) Infof(lc *LogContext, message *string) error {
+	return cla.record(lc, LevelInfo, message)
+}
+
+// Warningf records a warning message.
+func (cla *CaptureLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return cla.record(lc, LevelWarning, message)
+}
+
+// Errorf records an error message.
+func (cla *CaptureLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return cla.record(lc, LevelError, message)
+}
+
+// Criticalf records a critical message.
+func (cla *CaptureLogAdapter) Criticalf(lc *LogContext, message *string) error {
+	return cla.record(lc, LevelCritical, message)
+}
+
+func (cla *CaptureLogAdapter) record(lc *LogContext, methodLevel LogLevel, message *string) error {
+	ce := CapturedEntry{
+		Level:     methodLevel,
+		Formatted: *message,
+	}
+
+	if lc != nil {
+		ce.Level = messageLevel(lc, methodLevel)
+		ce.LevelName = lc.LevelName()
+		ce.Noun = lc.Noun()
+		ce.Message = lc.Message()
+		ce.Fields = lc.Fields()
+		ce.Error = lc.Error()
+		ce.Caller = lc.Caller()
+		ce.Time = lc.Time()
+	}
+
+	cla.m.Lock()
+	defer cla.m.Unlock()
+
+	cla.entries = append(cla.entries, ce)
+
+	return nil
+}
+
+// Entries returns a copy of everything recorded so far.
+func (cla *CaptureLogAdapter) Entries() []CapturedEntry {
+	cla.m.Lock()
+	defer cla.m.Unlock()
+
+	entries := make([]CapturedEntry, len(cla.entries))
+	copy(entries, cla.entries)
+
+	return entries
+}
+
+// Len returns the number of recorded entries.
+func (cla *CaptureLogAdapter) Len() int {
+	cla.m.Lock()
+	defer cla.m.Unlock()
+
+	return len(cla.entries)
+}
+
+// Reset discards everything recorded so far.
+func (cla *CaptureLogAdapter) Reset() {
+	cla.m.Lock()
+	defer cla.m.Unlock()
+
+	cla.entries = nil
+}
+
+// Filter returns the entries for which the callback returns true.
+func (cla *CaptureLogAdapter) Filter(cb func(ce CapturedEntry) bool) []CapturedEntry {
+	filtered := make([]CapturedEntry, 0)
+
+	for _, ce := range cla.Entries() {
+		if cb(ce) == true {
+			filtered = append(filtered, ce)
+		}
+	}
+
+	return filtered
+}
+
+// ByLevel returns the entries at the given level.
+func (cla *CaptureLogAdapter) ByLevel(level LogLevel) []CapturedEntry {
+	return cla.Filter(func(ce CapturedEntry) bool {
+		return ce.Level == level
+	})
+}
+
+// ByNoun returns the entries with the given noun.
+func (cla *CaptureLogAdapter) ByNoun(noun string) []CapturedEntry {
+	return cla.Filter(func(ce CapturedEntry) bool {
+		return ce.Noun == noun
+	})
+}
+
+// Contains returns whether any entry at the given level has a message
+// containing the substring.
+func (cla *CaptureLogAdapter) Contains(level LogLevel, substring string) bool {
+	matched := cla.Filter(func(ce CapturedEntry) bool {
+		return ce.Level == level && strings.Contains(ce.Message, substring) == true
+	})
+
+	return len(matched) > 0
+}
+
+// AssertLogged fails the test if no entry at the given level has a message
+// containing the substring.
+func (cla *CaptureLogAdapter) AssertLogged(tb TestingTB, level LogLevel, substring string) {
+	tb.Helper()
+
+	if cla.Contains(level, substring) == false {
+		tb.Errorf("no [%s] message containing [%s] was logged", level, substring)
+	}
+}
+
+// AssertNotLogged fails the test if any entry at the given level has a
+// message containing the substring.
+func (cla *CaptureLogAdapter) AssertNotLogged(tb TestingTB, level LogLevel, substring string) {
+	tb.Helper()
+
+	if cla.Contains(level, substring) == true {
+		tb.Errorf("a [%s] message containing [%s] was logged", level, substring)
+	}
+}
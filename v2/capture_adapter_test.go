@@ -0,0 +1,98 @@
+package log
+
+import (
+	e "errors"
+	"fmt"
+	"testing"
+)
+
+type recordingTB struct {
+	logs   []string
+	errors []string
+}
+
+func (rtb *recordingTB) Helper() {
+}
+
+func (rtb *recordingTB) Log(args ...interface{}) {
+	rtb.logs = append(rtb.logs, fmt.Sprint(args...))
+}
+
+func (rtb *recordingTB) Errorf(format string, args ...interface{}) {
+	rtb.errors = append(rtb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCaptureLogAdapter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	cla := NewCaptureLogAdapter()
+	AddAdapter("capture", cla)
+
+	l := NewLoggerWithAdapterName("captureTest", "capture").With(NewField("tenant", 11))
+	l.Infof(nil, "Info %s", "message")
+
+	err := e.New("an error happened")
+	NewLoggerWithAdapterName("otherTest", "capture").Errorf(nil, err, "Error message")
+
+	entries := cla.Entries()
+	if len(entries) != 2 || cla.Len() != 2 {
+		t.Fatalf("Entry count not correct: (%d)", len(entries))
+	}
+
+	ce := entries[0]
+	if ce.Level != LevelInfo || ce.LevelName != levelNameInfo || ce.Noun != "captureTest" {
+		t.Fatalf("Entry not correct: %v", ce)
+	} else if ce.Message != "Info message" || ce.Formatted != "captureTest: [INFO]  Info message tenant=11" {
+		t.Fatalf("Entry message not correct: %v", ce)
+	} else if value, found := ce.Fields.Get("tenant"); found != true || value != 11 {
+		t.Fatalf("Entry fields not correct: %v", ce.Fields)
+	}
+
+	if errorEntries := cla.ByLevel(LevelError); len(errorEntries) != 1 {
+		t.Fatalf("Error entries not correct: %v", errorEntries)
+	} else if Is(errorEntries[0].Error, err) != true {
+		t.Fatalf("Entry error not correct: %v", errorEntries[0].Error)
+	}
+
+	if nounEntries := cla.ByNoun("otherTest"); len(nounEntries) != 1 || nounEntries[0].Level != LevelError {
+		t.Fatalf("Noun entries not correct: %v", nounEntries)
+	}
+
+	if cla.Contains(LevelInfo, "Info") != true {
+		t.Fatalf("Expected info message.")
+	} else if cla.Contains(LevelWarning, "Info") != false {
+		t.Fatalf("Did not expect warning message.")
+	}
+
+	rtb := new(recordingTB)
+
+	cla.AssertLogged(rtb, LevelError, "Error")
+	cla.AssertNotLogged(rtb, LevelDebug, "Info")
+
+	if len(rtb.errors) != 0 {
+		t.Fatalf("Assertions should have passed: %v", rtb.errors)
+	}
+
+	cla.AssertLogged(rtb, LevelDebug, "Info")
+	cla.AssertNotLogged(rtb, LevelInfo, "Info")
+
+	if len(rtb.errors) != 2 {
+		t.Fatalf("Assertions should have failed: %v", rtb.errors)
+	} else if rtb.errors[0] != "no [debug] message containing [Info] was logged" {
+		t.Fatalf("Assertion message not correct: [%s]", rtb.errors[0])
+	}
+
+	cla.Reset()
+
+	if cla.Len() != 0 {
+		t.Fatalf("Entries not reset.")
+	}
+}
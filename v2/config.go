@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -14,102 +15,200 @@ const (
 	ckIncludeNouns           = "LogIncludeNouns"
 	ckExcludeNouns           = "LogExcludeNouns"
 	ckExcludeBypassLevelName = "LogExcludeBypassLevelName"
+	ckNounLevels             = "LogNounLevels"
 )
 
 // Other constants
 const (
-	defaultFormat    = "{{.Noun}}: [{{.Level}}] {{if eq .ExcludeBypass true}} [BYPASS]{{end}} {{.Message}}"
+	defaultFormat    = "{{.Noun}}: [{{.Level}}] {{if eq .ExcludeBypass true}} [BYPASS]{{end}} {{.Message}}{{if .Fields}} {{.Fields}}{{end}}"
 	defaultLevelName = levelNameInfo
 )
 
-// Config
-var (
-	// Alternative format.
-	format = defaultFormat
-
-	// Alternative adapter.
-	defaultAdapterName = ""
-
-	// Alternative level at which to display log-items
-	levelName = LogLevelName(strings.ToLower(string(defaultLevelName)))
-
-	// Configuration-driven comma-separated list of nouns to include.
-	includeNouns = ""
-
-	// Configuration-driven comma-separated list of nouns to exclude.
-	excludeNouns = ""
-
-	// excludeBypassLevelName is the level at which to disregard exclusion (if
-	// the severity of a message meets or exceed this, always display).
-	excludeBypassLevelName LogLevelName
-)
-
-// Other
-var (
-	configurationLoaded = false
-)
-
 // GetDefaultAdapterName returns the default adapter name. May be empty.
 func GetDefaultAdapterName() string {
-	return defaultAdapterName
+	return loadRegistry().defaultAdapterName
 }
 
 // SetDefaultAdapterName sets the default adapter. If not set, the first one
 // registered will be used.
 func SetDefaultAdapterName(name string) {
-	defaultAdapterName = name
+	updateRegistry(func(r *registry) {
+		r.defaultAdapterName = name
+	})
 }
 
-// LoadConfiguration loads the effective configuration.
+// LoadConfiguration loads the effective configuration. It may be called at any
+// time, and existing loggers will pick up the change before their next message.
 func LoadConfiguration(cp ConfigurationProvider) {
-	configuredDefaultAdapterName := cp.DefaultAdapterName()
+	updateRegistry(func(r *registry) {
+		configuredDefaultAdapterName := cp.DefaultAdapterName()
+
+		if configuredDefaultAdapterName != "" {
+			r.defaultAdapterName = configuredDefaultAdapterName
+		}
+
+		r.includeNouns = cp.IncludeNouns()
+		r.excludeNouns = cp.ExcludeNouns()
+		r.configIncludeFilters = parseNounFilters(r.includeNouns)
+		r.configExcludeFilters = parseNounFilters(r.excludeNouns)
+		r.excludeBypassLevelName = cp.ExcludeBypassLevelName()
+
+		if nlcp, ok := cp.(NounLevelsConfigurationProvider); ok == true {
+			r.nounLevels = nlcp.NounLevels()
+		} else {
+			r.nounLevels = ""
+		}
+
+		f := cp.Format()
+		if f != "" {
+			r.format = f
+		}
+
+		ln := cp.LevelName()
+		if ln != "" {
+			r.levelName = LogLevelName(strings.ToLower(string(ln)))
+		}
+
+		r.configurationLoaded = true
+	})
+}
+
+// Config is a snapshot of the global configuration, filters, and adapters
+// (see Snapshot). The fields can be changed before passing it to Restore.
+type Config struct {
+	Format                 string
+	DefaultAdapterName     string
+	LevelName              LogLevelName
+	IncludeNouns           string
+	ExcludeNouns           string
+	ExcludeBypassLevelName LogLevelName
+	NounLevels             string
+
+	// IsLoaded indicates whether a configuration had been loaded.
+	IsLoaded bool
+
+	// IncludeFilters and ExcludeFilters are the filters added with
+	// AddIncludeFilter and AddExcludeFilter (sorted).
+	IncludeFilters []string
+	ExcludeFilters []string
+
+	// NounLevelOverrides are the levels set with SetNounLevel.
+	NounLevelOverrides map[string]LogLevel
+
+	// Adapters are the registered adapters.
+	Adapters map[string]LogAdapter
+
+	CaptureCaller bool
+
+	// registry is the rest of the state (e.g. the registered levels, template
+	// functions, and clock), which is restored as-is.
+	registry *registry
+}
+
+// Snapshot returns the current configuration, filters, and adapters.
+func Snapshot() Config {
+	r := loadRegistry()
+
+	c := Config{
+		Format:                 r.format,
+		DefaultAdapterName:     r.defaultAdapterName,
+		LevelName:              r.levelName,
+		IncludeNouns:           r.includeNouns,
+		ExcludeNouns:           r.excludeNouns,
+		ExcludeBypassLevelName: r.excludeBypassLevelName,
+		NounLevels:             r.nounLevels,
+		IsLoaded:               r.configurationLoaded,
+		IncludeFilters:         make([]string, 0, len(r.includeFilters)),
+		ExcludeFilters:         make([]string, 0, len(r.excludeFilters)),
+		NounLevelOverrides:     make(map[string]LogLevel, len(r.nounLevelOverrides)),
+		Adapters:               make(map[string]LogAdapter, len(r.adapters)),
+		CaptureCaller:          r.captureCaller,
+		registry:               r,
+	}
 
-	if configuredDefaultAdapterName != "" {
-		defaultAdapterName = configuredDefaultAdapterName
+	for noun := range r.includeFilters {
+		c.IncludeFilters = append(c.IncludeFilters, noun)
 	}
 
-	includeNouns = cp.IncludeNouns()
-	excludeNouns = cp.ExcludeNouns()
-	excludeBypassLevelName = cp.ExcludeBypassLevelName()
+	sort.Strings(c.IncludeFilters)
 
-	f := cp.Format()
-	if f != "" {
-		format = f
+	for noun := range r.excludeFilters {
+		c.ExcludeFilters = append(c.ExcludeFilters, noun)
 	}
 
-	ln := cp.LevelName()
-	if ln != "" {
-		levelName = LogLevelName(strings.ToLower(string(ln)))
-	}
+	sort.Strings(c.ExcludeFilters)
 
-	configurationLoaded = true
-}
+	for noun, level := range r.nounLevelOverrides {
+		c.NounLevelOverrides[noun] = level
+	}
 
-func getConfigState() map[string]interface{} {
-	return map[string]interface{}{
-		"format":                 format,
-		"defaultAdapterName":     defaultAdapterName,
-		"levelName":              levelName,
-		"includeNouns":           includeNouns,
-		"excludeNouns":           excludeNouns,
-		"excludeBypassLevelName": excludeBypassLevelName,
+	for name, la := range r.adapters {
+		c.Adapters[name] = la
 	}
-}
 
-func setConfigState(config map[string]interface{}) {
-	format = config["format"].(string)
+	return c
+}
 
-	defaultAdapterName = config["defaultAdapterName"].(string)
+// Restore replaces the current configuration, filters, and adapters with the
+// given snapshot. Existing loggers pick it up before their next message.
+// A Config that was built by hand rather than taken with Snapshot is applied
+// on top of the built-in levels, template functions, and system clock.
+func Restore(c Config) {
+	includeFilters := make(map[string]nounPattern, len(c.IncludeFilters))
+	for _, noun := range c.IncludeFilters {
+		includeFilters[noun] = newNounPattern(noun)
+	}
 
-	levelName = config["levelName"].(LogLevelName)
-	levelName = LogLevelName(strings.ToLower(string(levelName)))
+	excludeFilters := make(map[string]nounPattern, len(c.ExcludeFilters))
+	for _, noun := range c.ExcludeFilters {
+		excludeFilters[noun] = newNounPattern(noun)
+	}
 
-	includeNouns = config["includeNouns"].(string)
-	excludeNouns = config["excludeNouns"].(string)
-	excludeBypassLevelName = config["excludeBypassLevelName"].(LogLevelName)
+	updateRegistry(func(r *registry) {
+		generation := r.generation
+		templateFuncsVersion := r.templateFuncsVersion
+
+		if c.registry != nil {
+			*r = *c.registry.clone()
+		} else {
+			*r = *newBaseRegistry()
+		}
+
+		r.generation = generation
+
+		// The template functions may differ from the current ones, so the
+		// version must move forward for the template to be parsed again.
+		r.templateFuncsVersion = templateFuncsVersion + 1
+
+		r.format = c.Format
+		r.defaultAdapterName = c.DefaultAdapterName
+		r.levelName = LogLevelName(strings.ToLower(string(c.LevelName)))
+		r.includeNouns = c.IncludeNouns
+		r.excludeNouns = c.ExcludeNouns
+		r.configIncludeFilters = parseNounFilters(c.IncludeNouns)
+		r.configExcludeFilters = parseNounFilters(c.ExcludeNouns)
+		r.excludeBypassLevelName = c.ExcludeBypassLevelName
+		r.nounLevels = c.NounLevels
+		r.configurationLoaded = c.IsLoaded
+		r.includeFilters = includeFilters
+		r.excludeFilters = excludeFilters
+		r.captureCaller = c.CaptureCaller
+
+		r.nounLevelOverrides = make(map[string]LogLevel, len(c.NounLevelOverrides))
+		for noun, level := range c.NounLevelOverrides {
+			r.nounLevelOverrides[noun] = level
+		}
+
+		r.adapters = make(map[string]LogAdapter, len(c.Adapters))
+		for name, la := range c.Adapters {
+			r.adapters[name] = la
+		}
+	})
 }
 
 func getConfigDump() string {
+	r := loadRegistry()
+
 	return fmt.Sprintf(
 		"Current configuration:\n"+
 			"  FORMAT=[%s]\n"+
@@ -117,13 +216,14 @@ func getConfigDump() string {
 			"  LEVEL-NAME=[%s]\n"+
 			"  INCLUDE-NOUNS=[%s]\n"+
 			"  EXCLUDE-NOUNS=[%s]\n"+
-			"  EXCLUDE-BYPASS-LEVEL-NAME=[%s]",
-		format, defaultAdapterName, levelName, includeNouns, excludeNouns, excludeBypassLevelName)
+			"  EXCLUDE-BYPASS-LEVEL-NAME=[%s]\n"+
+			"  NOUN-LEVELS=[%s]",
+		r.format, r.defaultAdapterName, r.levelName, r.includeNouns, r.excludeNouns, r.excludeBypassLevelName, r.nounLevels)
 }
 
 // IsConfigurationLoaded indicates whether a config has been loaded.
 func IsConfigurationLoaded() bool {
-	return configurationLoaded
+	return loadRegistry().configurationLoaded
 }
 
 // ConfigurationProvider describes minimal configuration implementation.
@@ -151,6 +251,15 @@ type ConfigurationProvider interface {
 	ExcludeBypassLevelName() LogLevelName
 }
 
+// NounLevelsConfigurationProvider is implemented by configuration-providers
+// that also provide per-noun levels. Otherwise, no noun levels are configured.
+type NounLevelsConfigurationProvider interface {
+	// Configuration-driven comma-separated list of "noun=level" pairs (e.g.
+	// "db=debug,http=warning") that override the level for those nouns and
+	// the nouns beneath them. Defaults to empty.
+	NounLevels() string
+}
+
 // EnvironmentConfigurationProvider configuration-provider.
 type EnvironmentConfigurationProvider struct {
 }
@@ -192,6 +301,11 @@ func (ecp *EnvironmentConfigurationProvider) ExcludeBypassLevelName() LogLevelNa
 	return LogLevelName(os.Getenv(ckExcludeBypassLevelName))
 }
 
+// NounLevels returns inlined set of per-noun levels.
+func (ecp *EnvironmentConfigurationProvider) NounLevels() string {
+	return os.Getenv(ckNounLevels)
+}
+
 // StaticConfigurationProvider configuration-provider.
 type StaticConfigurationProvider struct {
 	format                 string
@@ -200,6 +314,7 @@ type StaticConfigurationProvider struct {
 	includeNouns           string
 	excludeNouns           string
 	excludeBypassLevelName LogLevelName
+	nounLevels             string
 }
 
 // NewStaticConfigurationProvider returns a new StaticConfigurationProvider
@@ -225,7 +340,7 @@ func (scp *StaticConfigurationProvider) SetLevelName(levelName LogLevelName) {
 
 // SetLevel sets the effective level (using the constant).
 func (scp *StaticConfigurationProvider) SetLevel(level LogLevel) {
-	scp.levelName = levelNameMapR[level]
+	scp.levelName = loadRegistry().levelNamesByLevel[level]
 }
 
 // SetIncludeNouns sets an inlined set of nouns to include.
@@ -244,6 +359,12 @@ func (scp *StaticConfigurationProvider) SetExcludeBypassLevelName(excludeBypassL
 	scp.excludeBypassLevelName = excludeBypassLevelName
 }
 
+// SetNounLevels sets an inlined set of per-noun levels (e.g.
+// "db=debug,http=warning").
+func (scp *StaticConfigurationProvider) SetNounLevels(nounLevels string) {
+	scp.nounLevels = nounLevels
+}
+
 // Format returns the format string.
 func (scp *StaticConfigurationProvider) Format() string {
 	return scp.format
@@ -275,6 +396,11 @@ func (scp *StaticConfigurationProvider) ExcludeBypassLevelName() LogLevelName {
 	return scp.excludeBypassLevelName
 }
 
+// NounLevels returns inlined set of per-noun levels.
+func (scp *StaticConfigurationProvider) NounLevels() string {
+	return scp.nounLevels
+}
+
 func init() {
 	// Do the initial configuration-load from the environment. We gotta seed it
 	// with something for simplicity's sake.
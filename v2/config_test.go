@@ -0,0 +1,169 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName("WARNING")
+	scp.SetFormat("{{.Message}}")
+	scp.SetExcludeBypassLevelName(levelNameError)
+	scp.SetNounLevels("db=debug")
+
+	LoadConfiguration(scp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter()
+	AddAdapter("test", tla)
+
+	AddIncludeFilter("b.**")
+	AddIncludeFilter("a")
+	AddExcludeFilter("b.c")
+	SetNounLevel("http", LevelError)
+
+	c := Snapshot()
+
+	if c.Format != "{{.Message}}" || c.LevelName != levelNameWarning || c.DefaultAdapterName != "test" {
+		t.Fatalf("Configuration not correct: %v", c)
+	} else if c.ExcludeBypassLevelName != levelNameError || c.NounLevels != "db=debug" || c.IsLoaded != true {
+		t.Fatalf("Configuration not correct: %v", c)
+	} else if reflect.DeepEqual(c.IncludeFilters, []string{"a", "b.**"}) != true {
+		t.Fatalf("Include filters not correct: %v", c.IncludeFilters)
+	} else if reflect.DeepEqual(c.ExcludeFilters, []string{"b.c"}) != true {
+		t.Fatalf("Exclude filters not correct: %v", c.ExcludeFilters)
+	} else if reflect.DeepEqual(c.NounLevelOverrides, map[string]LogLevel{"http": LevelError}) != true {
+		t.Fatalf("Noun-level overrides not correct: %v", c.NounLevelOverrides)
+	} else if len(c.Adapters) != 1 || c.Adapters["test"] != tla {
+		t.Fatalf("Adapters not correct: %v", c.Adapters)
+	}
+
+	// Changing the snapshot should not change the configuration.
+
+	c.Adapters["other"] = newTestLogAdapter()
+	c.NounLevelOverrides["other"] = LevelDebug
+
+	if _, found := loadRegistry().adapters["other"]; found != false {
+		t.Fatalf("Snapshot adapters should be a copy.")
+	} else if _, found := loadRegistry().nounLevelOverrides["other"]; found != false {
+		t.Fatalf("Snapshot noun-level overrides should be a copy.")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	AddExcludeFilter("restoreTest.hidden")
+
+	saved := Snapshot()
+
+	l := NewLogger("restoreTest.hidden")
+
+	// Temporarily change everything.
+
+	ClearAdapters()
+
+	other := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("other", other)
+
+	RemoveExcludeFilter("restoreTest.hidden")
+	SetNounLevel("restoreTest", LevelError)
+
+	l.Errorf(nil, nil, "Error message")
+
+	if other.errorTriggered != true {
+		t.Fatalf("Temporary configuration not applied.")
+	}
+
+	Restore(saved)
+
+	l.Errorf(nil, nil, "Error message")
+
+	if tla.errorTriggered != false {
+		t.Fatalf("Exclude filter not restored.")
+	}
+
+	NewLogger("restoreTest").Debugf(nil, "Debug message")
+
+	if tla.debugTriggered != true {
+		t.Fatalf("Adapter and noun-levels not restored.")
+	}
+
+	// A modified snapshot can be restored too.
+
+	saved.LevelName = levelNameError
+	saved.ExcludeFilters = nil
+
+	Restore(saved)
+
+	l.Infof(nil, "Info message")
+
+	if tla.infoTriggered != false {
+		t.Fatalf("Modified level not applied.")
+	}
+
+	l.Errorf(nil, nil, "Error message")
+
+	if tla.errorTriggered != true {
+		t.Fatalf("Modified filters not applied.")
+	}
+}
+
+func TestRestore__HandBuilt(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+
+	Restore(Config{
+		Format:       "{{pad 8 .Level}}|{{.Message}}",
+		LevelName:    levelNameDebug,
+		IncludeNouns: "handBuilt",
+		IsLoaded:     true,
+		Adapters: map[string]LogAdapter{
+			"test": tla,
+		},
+	})
+
+	l := NewLoggerWithAdapterName("handBuilt", "test")
+	l.Debugf(nil, "Debug message")
+
+	if tla.lastMessage != "DEBUG   |Debug message" {
+		t.Fatalf("Built-in template functions not available: [%s]", tla.lastMessage)
+	} else if tla.lastContext.Time().IsZero() == true {
+		t.Fatalf("Clock not available.")
+	}
+
+	l.Logf(nil, LevelTrace, "Trace message")
+
+	if tla.lastMessage != "DEBUG   |Debug message" {
+		t.Fatalf("Built-in levels not available: [%s]", tla.lastMessage)
+	}
+
+	tla.debugTriggered = false
+	NewLoggerWithAdapterName("other", "test").Debugf(nil, "Debug message")
+
+	if tla.debugTriggered != false {
+		t.Fatalf("Include-nouns not applied.")
+	}
+}
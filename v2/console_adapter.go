@@ -1,42 +1,303 @@
 package log
 
 import (
+	"io"
 	golog "log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
 )
 
-// ConsoleLogAdapter prints logging to STDOUT.
+const (
+	// ConsoleAdapterName is the name that can be configured as the default
+	// adapter-name to log to the console without registering an adapter.
+	ConsoleAdapterName = "console"
+
+	// ColorConsoleAdapterName is the name that can be configured as the
+	// default adapter-name to log to the console in color (when it's a
+	// terminal) without registering an adapter.
+	ColorConsoleAdapterName = "console-color"
+)
+
+const (
+	ansiBypass = "\x1b[1;35m"
+	ansiStack  = "\x1b[2m"
+)
+
+// ColorMode determines whether a console adapter writes color.
+type ColorMode int
+
+const (
+	// ColorNever never writes color.
+	ColorNever ColorMode = iota
+
+	// ColorAuto writes color if the writer is a terminal. See IsColorEnabled.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways always writes color.
+	ColorAlways ColorMode = iota
+)
+
+// ConsoleLogAdapterConfig describes where and how a ConsoleLogAdapter writes.
+type ConsoleLogAdapterConfig struct {
+	// Writer receives messages. Defaults to STDERR.
+	Writer io.Writer
+
+	// ErrorWriter, if not nil, receives messages at or above ErrorLevel
+	// instead of Writer (e.g. warnings and above to STDERR and the rest to
+	// STDOUT).
+	ErrorWriter io.Writer
+
+	// ErrorLevel is the level at which messages go to ErrorWriter. Defaults to
+	// LevelWarning if nil.
+	ErrorLevel *LogLevel
+
+	// Prefix and Flags are as taken by the standard library's log.New().
+	// Note that the file flags (e.g. log.Lshortfile) will refer to the
+	// adapter. Use SetCallerCapture() and the "Caller" template value instead.
+	Prefix string
+	Flags  int
+
+	// Color determines whether messages are colored by level.
+	Color ColorMode
+}
+
+// ConsoleLogAdapter prints logging. By default, it logs via the standard
+// library's global logger (which writes to STDERR by default, with its own
+// flags and prefix). Use NewConsoleLogAdapterWithConfig to log independently
+// of it.
 type ConsoleLogAdapter struct {
+	// logger and errorLogger are nil when logging via the global logger.
+	logger      *golog.Logger
+	errorLogger *golog.Logger
+	errorLevel  LogLevel
+
+	isColored      bool
+	isErrorColored bool
+
+	// isGlobalColorAuto indicates that, when logging via the global logger,
+	// color is written if the global logger's writer is a terminal. Since the
+	// writer can be changed at any time, this is checked when logging and the
+	// result is cached for the last writer seen.
+	isGlobalColorAuto     bool
+	globalWriter          io.Writer
+	isGlobalWriterColored bool
+
+	// m keeps lines whole when both loggers share a writer.
+	m sync.Mutex
 }
 
-// NewConsoleLogAdapter returns a new ConsoleLogAdapter.
+// NewConsoleLogAdapter returns a new ConsoleLogAdapter that logs via the
+// standard library's global logger.
 func NewConsoleLogAdapter() LogAdapter {
 	return new(ConsoleLogAdapter)
 }
 
+// NewColorConsoleLogAdapter returns a ConsoleLogAdapter that logs via the
+// standard library's global logger and colors messages by level if that
+// logger's writer (see log.SetOutput) is a terminal. See IsColorEnabled for
+// how this can be overridden from the environment.
+func NewColorConsoleLogAdapter() LogAdapter {
+	return &ConsoleLogAdapter{
+		isGlobalColorAuto: true,
+	}
+}
+
+// NewConsoleLogAdapterWithConfig returns a ConsoleLogAdapter that writes to
+// the given writers with its own prefix and flags.
+func NewConsoleLogAdapterWithConfig(config ConsoleLogAdapterConfig) *ConsoleLogAdapter {
+	w := config.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+
+	errorLevel := LevelWarning
+	if config.ErrorLevel != nil {
+		errorLevel = *config.ErrorLevel
+	}
+
+	cla := &ConsoleLogAdapter{
+		logger:     golog.New(w, config.Prefix, config.Flags),
+		errorLevel: errorLevel,
+		isColored:  isColorModeEnabled(config.Color, w),
+	}
+
+	if config.ErrorWriter != nil {
+		cla.errorLogger = golog.New(config.ErrorWriter, config.Prefix, config.Flags)
+		cla.isErrorColored = isColorModeEnabled(config.Color, config.ErrorWriter)
+	}
+
+	return cla
+}
+
+// Tracef logs a trace message.
+func (cla *ConsoleLogAdapter) Tracef(lc *LogContext, message *string) error {
+	return cla.write(lc, message)
+}
+
 // Debugf logs a debugging message.
 func (cla *ConsoleLogAdapter) Debugf(lc *LogContext, message *string) error {
-	golog.Println(*message)
-
-	return nil
+	return cla.write(lc, message)
 }
 
 // Infof logs an info message.
 func (cla *ConsoleLogAdapter) Infof(lc *LogContext, message *string) error {
-	golog.Println(*message)
-
-	return nil
+	return cla.write(lc, message)
 }
 
 // Warningf logs a warning message.
 func (cla *ConsoleLogAdapter) Warningf(lc *LogContext, message *string) error {
-	golog.Println(*message)
-
-	return nil
+	return cla.write(lc, message)
 }
 
 // Errorf logs an error message.
 func (cla *ConsoleLogAdapter) Errorf(lc *LogContext, message *string) error {
-	golog.Println(*message)
+	return cla.write(lc, message)
+}
+
+// Criticalf logs a critical message.
+func (cla *ConsoleLogAdapter) Criticalf(lc *LogContext, message *string) error {
+	return cla.write(lc, message)
+}
+
+func (cla *ConsoleLogAdapter) write(lc *LogContext, message *string) error {
+	logger := cla.logger
+	isColored := cla.isColored
+
+	if cla.errorLogger != nil && lc != nil && lc.Level() >= cla.errorLevel {
+		logger = cla.errorLogger
+		isColored = cla.isErrorColored
+	}
+
+	if logger == nil {
+		// Don't log back into ourselves if the global logger is redirected.
+		if original := stdlibConsoleLogger(); original != nil {
+			return original.Output(2, cla.colorizeGlobal(lc, *message, original.Writer()))
+		}
+
+		golog.Println(cla.colorizeGlobal(lc, *message, golog.Writer()))
+		return nil
+	}
+
+	s := *message
+
+	if isColored == true && lc != nil {
+		s = colorizeMessage(lc, s)
+	}
+
+	cla.m.Lock()
+	defer cla.m.Unlock()
+
+	return logger.Output(2, s)
+}
+
+// colorizeGlobal colors a message that's being written via a global logger
+// that writes to the given writer.
+func (cla *ConsoleLogAdapter) colorizeGlobal(lc *LogContext, s string, w io.Writer) string {
+	if cla.isGlobalColorAuto == false || lc == nil {
+		return s
+	}
+
+	var isColored bool
+
+	if w == nil || reflect.TypeOf(w).Comparable() == false {
+		isColored = isColorModeEnabled(ColorAuto, w)
+	} else {
+		cla.m.Lock()
+
+		if w != cla.globalWriter {
+			cla.globalWriter = w
+			cla.isGlobalWriterColored = isColorModeEnabled(ColorAuto, w)
+		}
+
+		isColored = cla.isGlobalWriterColored
+
+		cla.m.Unlock()
+	}
+
+	if isColored == false {
+		return s
+	}
+
+	return colorizeMessage(lc, s)
+}
+
+// colorizeMessage colors the message by level. The [BYPASS] marker is
+// highlighted and, if an error was logged, the stack (everything after the
+// first line) is dimmed.
+func colorizeMessage(lc *LogContext, s string) string {
+	color := levelColors[adapterLevel(lc.Level())]
+
+	first := s
+	rest := ""
+
+	if lc.Error() != nil {
+		if i := strings.Index(s, "\n"); i != -1 {
+			first = s[:i]
+			rest = s[i+1:]
+		}
+	}
+
+	if lc.ExcludeBypass() == true {
+		first = strings.Replace(first, "[BYPASS]", ansiBypass+"[BYPASS]"+ansiReset+color, 1)
+	}
+
+	s = color + first + ansiReset
+
+	if rest != "" {
+		s += "\n" + ansiStack + rest + ansiReset
+	}
+
+	return s
+}
+
+// IsColorEnabled returns whether color should be written to the given file.
+// If FORCE_COLOR is set, color is enabled unless it's "0" or "false". If not,
+// and NO_COLOR is set to anything, color is disabled. Otherwise, color is
+// enabled if the file is a terminal and TERM isn't "dumb".
+func IsColorEnabled(f *os.File) bool {
+	if forceColor, found := os.LookupEnv("FORCE_COLOR"); found == true {
+		forceColor = strings.ToLower(strings.TrimSpace(forceColor))
+		return forceColor != "0" && forceColor != "false"
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return isTerminal(f)
+}
+
+// isColorModeEnabled returns whether color should be written to the given
+// writer.
+func isColorModeEnabled(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorAuto:
+		f, _ := w.(*os.File)
+		return IsColorEnabled(f)
+	}
+
+	return false
+}
+
+// isTerminal returns whether the file is a character device (e.g. a terminal
+// rather than a pipe or regular file).
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
 
-	return nil
+	return fi.Mode()&os.ModeCharDevice != 0
 }
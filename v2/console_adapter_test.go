@@ -1,6 +1,13 @@
 package log
 
 import (
+	"bytes"
+	e "errors"
+	"io/ioutil"
+	golog "log"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -25,3 +32,338 @@ func TestConsole(t *testing.T) {
 		t.Error("Adapter wasn't initialized correctly.")
 	}
 }
+
+// setTestEnv sets (or, if value is nil, unsets) an environment variable and
+// returns a function that restores it.
+func setTestEnv(name string, value *string) func() {
+	original, found := os.LookupEnv(name)
+
+	if value == nil {
+		os.Unsetenv(name)
+	} else {
+		os.Setenv(name, *value)
+	}
+
+	return func() {
+		if found == true {
+			os.Setenv(name, original)
+		} else {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestIsColorEnabled(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	PanicIf(err)
+
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	on := "1"
+	off := "0"
+
+	cases := []struct {
+		forceColor *string
+		noColor    *string
+		expected   bool
+	}{
+		{nil, nil, false},
+		{&on, nil, true},
+		{&on, &on, true},
+		{&off, nil, false},
+		{nil, &on, false},
+	}
+
+	for i, c := range cases {
+		restoreForceColor := setTestEnv("FORCE_COLOR", c.forceColor)
+		restoreNoColor := setTestEnv("NO_COLOR", c.noColor)
+
+		isEnabled := IsColorEnabled(f)
+
+		restoreForceColor()
+		restoreNoColor()
+
+		if isEnabled != c.expected {
+			t.Fatalf("Case (%d) not correct: [%v]", i, isEnabled)
+		}
+	}
+}
+
+func TestNewColorConsoleLogAdapter__GlobalWriter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	defer setTestEnv("FORCE_COLOR", nil)()
+	defer setTestEnv("NO_COLOR", nil)()
+	defer setTestEnv("TERM", nil)()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	cla := NewColorConsoleLogAdapter().(*ConsoleLogAdapter)
+	AddAdapter("console", cla)
+
+	l := NewLoggerWithAdapterName("consoleTest", "console")
+
+	originalWriter := golog.Writer()
+	originalFlags := golog.Flags()
+
+	defer func() {
+		golog.SetOutput(originalWriter)
+		golog.SetFlags(originalFlags)
+	}()
+
+	golog.SetFlags(0)
+
+	// A character device counts as a terminal.
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	PanicIf(err)
+
+	defer devNull.Close()
+
+	golog.SetOutput(devNull)
+	l.Warningf(nil, "Warning message")
+
+	if cla.isGlobalWriterColored != true {
+		t.Fatalf("Character device should be colored.")
+	}
+
+	// The global logger's writer is changed after the adapter was created.
+
+	f, err := ioutil.TempFile("", "")
+	PanicIf(err)
+
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	golog.SetOutput(f)
+	l.Warningf(nil, "Warning message")
+
+	content, err := ioutil.ReadFile(f.Name())
+	PanicIf(err)
+
+	if string(content) != "consoleTest: [WARNING]  Warning message\n" {
+		t.Fatalf("Color should not be written to a file: [%q]", string(content))
+	}
+}
+
+func TestColorizeMessage(t *testing.T) {
+	lc := &LogContext{
+		level: LevelWarning,
+	}
+
+	if s := colorizeMessage(lc, "a message"); s != levelColors[LevelWarning]+"a message"+ansiReset {
+		t.Fatalf("Message not colored correctly: [%q]", s)
+	}
+
+	lc = &LogContext{
+		level:         LevelError,
+		excludeBypass: true,
+		err:           Wrap(e.New("an error")),
+	}
+
+	s := colorizeMessage(lc, "noun: [ERROR]  [BYPASS] a message\nthe stack")
+	red := levelColors[LevelError]
+
+	expected := red + "noun: [ERROR]  " + ansiBypass + "[BYPASS]" + ansiReset + red + " a message" + ansiReset + "\n" + ansiStack + "the stack" + ansiReset
+	if s != expected {
+		t.Fatalf("Message not colored correctly: [%q]", s)
+	}
+}
+
+func TestColorConsoleLogAdapter__DefaultAdapterName(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	restoreForceColor := setTestEnv("FORCE_COLOR", nil)
+	defer restoreForceColor()
+
+	ClearAdapters()
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetDefaultAdapterName(ColorConsoleAdapterName)
+
+	LoadConfiguration(scp)
+
+	b := new(bytes.Buffer)
+
+	golog.SetOutput(b)
+	golog.SetFlags(0)
+
+	defer func() {
+		golog.SetOutput(os.Stderr)
+		golog.SetFlags(golog.LstdFlags)
+	}()
+
+	l := NewLogger("consoleTest")
+	l.Infof(nil, "Info message")
+
+	if _, ok := l.Adapter().(*ConsoleLogAdapter); ok != true {
+		t.Fatalf("Built-in adapter not used.")
+	} else if b.String() != "consoleTest: [INFO]  Info message\n" {
+		t.Fatalf("Message not correct (color should be disabled for a non-terminal): [%q]", b.String())
+	}
+}
+
+func TestNewConsoleLogAdapterWithConfig(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	errorLevel := LevelWarning
+
+	cla := NewConsoleLogAdapterWithConfig(ConsoleLogAdapterConfig{
+		Writer:      stdout,
+		ErrorWriter: stderr,
+		ErrorLevel:  &errorLevel,
+		Prefix:      "app ",
+	})
+
+	AddAdapter("console", cla)
+
+	// The global logger's configuration should not apply.
+	golog.SetPrefix("global ")
+	defer golog.SetPrefix("")
+
+	l := NewLoggerWithAdapterName("consoleTest", "console")
+
+	l.Infof(nil, "Info message")
+	l.Warningf(nil, "Warning message")
+
+	if stdout.String() != "app consoleTest: [INFO]  Info message\n" {
+		t.Fatalf("STDOUT not correct: [%s]", stdout.String())
+	} else if stderr.String() != "app consoleTest: [WARNING]  Warning message\n" {
+		t.Fatalf("STDERR not correct: [%s]", stderr.String())
+	}
+}
+
+func TestNewConsoleLogAdapterWithConfig__ErrorLevel(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	// Warnings and above go to the error-writer if the level isn't given.
+
+	AddAdapter("default", NewConsoleLogAdapterWithConfig(ConsoleLogAdapterConfig{
+		Writer:      stdout,
+		ErrorWriter: stderr,
+	}))
+
+	l := NewLoggerWithAdapterName("consoleTest", "default")
+
+	l.Tracef(nil, "Trace message")
+	l.Infof(nil, "Info message")
+	l.Warningf(nil, "Warning message")
+
+	if stdout.String() != "consoleTest: [INFO]  Info message\n" {
+		t.Fatalf("STDOUT not correct: [%s]", stdout.String())
+	} else if stderr.String() != "consoleTest: [WARNING]  Warning message\n" {
+		t.Fatalf("STDERR not correct: [%s]", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+
+	// An explicit level is honored, including the lowest one.
+
+	errorLevel := LevelTrace
+
+	AddAdapter("explicit", NewConsoleLogAdapterWithConfig(ConsoleLogAdapterConfig{
+		Writer:      stdout,
+		ErrorWriter: stderr,
+		ErrorLevel:  &errorLevel,
+	}))
+
+	NewLoggerWithAdapterName("consoleTest", "explicit").Infof(nil, "Info message")
+
+	if stdout.Len() != 0 {
+		t.Fatalf("STDOUT should be empty: [%s]", stdout.String())
+	} else if stderr.String() != "consoleTest: [INFO]  Info message\n" {
+		t.Fatalf("STDERR not correct: [%s]", stderr.String())
+	}
+}
+
+func TestNewConsoleLogAdapterWithConfig__Concurrent(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	// Both writers share a buffer, which is not goroutine-safe by itself.
+	b := new(bytes.Buffer)
+
+	cla := NewConsoleLogAdapterWithConfig(ConsoleLogAdapterConfig{
+		Writer:      b,
+		ErrorWriter: b,
+		Color:       ColorAlways,
+	})
+
+	AddAdapter("console", cla)
+
+	l := NewLoggerWithAdapterName("consoleTest", "console")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				if j%2 == 0 {
+					l.Infof(nil, "Message (%d) (%d)", i, j)
+				} else {
+					l.Warningf(nil, "Message (%d) (%d)", i, j)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 200 {
+		t.Fatalf("Line count not correct: (%d)", len(lines))
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\x1b[") != true || strings.HasSuffix(line, ansiReset) != true {
+			t.Fatalf("Line not whole: [%q]", line)
+		}
+	}
+}
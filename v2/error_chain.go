@@ -0,0 +1,133 @@
+package log
+
+import (
+	e "errors"
+	"reflect"
+
+	"github.com/go-errors/errors"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// unwrapError returns the errors directly wrapped by the given one. This
+// understands Unwrap() (returning one error or several) as well as
+// *errors.Error (which does not implement Unwrap()).
+func unwrapError(err error) []error {
+	switch x := err.(type) {
+	case *errors.Error:
+		if x.Err == nil {
+			return nil
+		}
+
+		return []error{x.Err}
+	case interface{ Unwrap() []error }:
+		return x.Unwrap()
+	case interface{ Unwrap() error }:
+		if unwrapped := x.Unwrap(); unwrapped != nil {
+			return []error{unwrapped}
+		}
+	}
+
+	return nil
+}
+
+// walkError calls the callback for the error and then for every error in its
+// chain (depth-first) until the callback returns true.
+func walkError(err error, cb func(err error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if cb(err) == true {
+		return true
+	}
+
+	for _, unwrapped := range unwrapError(err) {
+		if walkError(unwrapped, cb) == true {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findStackError returns the innermost (deepest) stack-wrapped error in the
+// chain or nil if there isn't one.
+func findStackError(err error) *errors.Error {
+	var innermost *errors.Error
+	innermostDepth := -1
+
+	var find func(err error, depth int)
+	find = func(err error, depth int) {
+		if es, ok := err.(*errors.Error); ok == true && depth > innermostDepth {
+			innermost = es
+			innermostDepth = depth
+		}
+
+		for _, unwrapped := range unwrapError(err) {
+			find(unwrapped, depth+1)
+		}
+	}
+
+	find(err, 0)
+
+	return innermost
+}
+
+// wrap returns a stack-wrapped error. If the error is already stack-wrapped it
+// is returned as-is. If something deeper in its chain is stack-wrapped, the
+// error is returned with that stack rather than a new one. Otherwise, the
+// stack starts skip frames above the caller.
+func wrap(err interface{}, skip int) *errors.Error {
+	if es, ok := err.(*errors.Error); ok == true {
+		return es
+	}
+
+	if asError, ok := err.(error); ok == true {
+		// If the innermost error has a prefix, its message can't be replaced,
+		// so it gets a new stack instead.
+		if inner := findStackError(asError); inner != nil && inner.Err != nil && inner.Error() == inner.Err.Error() {
+			copied := *inner
+			copied.Err = asError
+
+			return &copied
+		}
+	}
+
+	return errors.Wrap(err, skip+1)
+}
+
+// As finds the first error in the chain that can be assigned to the value
+// that target points to, sets it, and returns true. It works like the
+// standard library's errors.As() but also unwraps stack-wrapped errors and
+// errors that wrap several others.
+func As(err error, target interface{}) bool {
+	if target == nil {
+		Panic(e.New("target can not be nil"))
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() == true {
+		Panic(e.New("target must be a non-nil pointer"))
+	}
+
+	targetType := value.Type().Elem()
+	if targetType.Kind() != reflect.Interface && targetType.Implements(errorType) == false {
+		Panic(e.New("target must point to an interface or to a type that implements error"))
+	}
+
+	return walkError(err, func(err error) bool {
+		if reflect.TypeOf(err).AssignableTo(targetType) == true {
+			value.Elem().Set(reflect.ValueOf(err))
+			return true
+		}
+
+		if x, ok := err.(interface{ As(interface{}) bool }); ok == true && x.As(target) == true {
+			return true
+		}
+
+		return false
+	})
+}
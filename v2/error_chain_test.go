@@ -0,0 +1,204 @@
+package log
+
+import (
+	e "errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+type multiError struct {
+	errs []error
+}
+
+func (me *multiError) Error() string {
+	messages := make([]string, len(me.errs))
+	for i, err := range me.errs {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (me *multiError) Unwrap() []error {
+	return me.errs
+}
+
+type codeError struct {
+	code int
+}
+
+func (ce codeError) Error() string {
+	return fmt.Sprintf("code (%d)", ce.code)
+}
+
+type matchingError struct{}
+
+func (me matchingError) Error() string {
+	return "matching error"
+}
+
+func (me matchingError) Is(err error) bool {
+	return err == os.ErrNotExist
+}
+
+func TestIs__Chain(t *testing.T) {
+	sentinel := e.New("sentinel error")
+
+	cases := map[string]error{
+		"stdlib":         fmt.Errorf("outer: %w", sentinel),
+		"go-errors":      fmt.Errorf("outer: %w", errors.Wrap(sentinel, 0)),
+		"wrap":           Wrap(fmt.Errorf("outer: %w", sentinel)),
+		"errorf":         Errorf("outer: %w", sentinel),
+		"errorf-wrapped": Errorf("outer: %w", Errorf("inner: %w", sentinel)),
+		"multi":          &multiError{errs: []error{e.New("other error"), fmt.Errorf("inner: %w", sentinel)}},
+		"wrapped-multi":  Wrap(fmt.Errorf("outer: %w", &multiError{errs: []error{Wrap(sentinel)}})),
+	}
+
+	for name, err := range cases {
+		if Is(err, sentinel) != true {
+			t.Fatalf("Sentinel not found in [%s] chain.", name)
+		} else if Is(err, Wrap(sentinel)) != true {
+			t.Fatalf("Wrapped sentinel not found in [%s] chain.", name)
+		} else if Is(err, e.New("sentinel error")) != false {
+			t.Fatalf("Different error with the same message should not match in [%s] chain.", name)
+		}
+	}
+}
+
+func TestIs__Nil(t *testing.T) {
+	if Is(nil, nil) != true {
+		t.Fatalf("Nil should match nil.")
+	} else if Is(nil, os.ErrNotExist) != false {
+		t.Fatalf("Nil should not match an error.")
+	} else if Is(os.ErrNotExist, nil) != false {
+		t.Fatalf("An error should not match nil.")
+	}
+}
+
+func TestIs__Method(t *testing.T) {
+	err := Errorf("outer: %w", matchingError{})
+
+	if Is(err, os.ErrNotExist) != true {
+		t.Fatalf("Is() method not consulted.")
+	} else if Is(err, os.ErrExist) != false {
+		t.Fatalf("Is() method should not match other errors.")
+	}
+}
+
+func TestIs__Uncomparable(t *testing.T) {
+	me := &multiError{errs: []error{os.ErrNotExist}}
+
+	if Is(Wrap(me), os.ErrNotExist) != true {
+		t.Fatalf("Error not found behind an uncomparable error.")
+	}
+}
+
+func TestAs(t *testing.T) {
+	cases := map[string]error{
+		"stdlib":    fmt.Errorf("outer: %w", codeError{code: 11}),
+		"go-errors": errors.Wrap(codeError{code: 11}, 0),
+		"errorf":    Errorf("outer: %w", Wrap(codeError{code: 11})),
+		"multi":     Wrap(&multiError{errs: []error{e.New("other error"), codeError{code: 11}}}),
+	}
+
+	for name, err := range cases {
+		var ce codeError
+		if As(err, &ce) != true {
+			t.Fatalf("Error not found in [%s] chain.", name)
+		} else if ce.code != 11 {
+			t.Fatalf("Error not assigned from [%s] chain: (%d)", name, ce.code)
+		}
+
+		var me *multiError
+		if As(err, &me) != (name == "multi") {
+			t.Fatalf("Multi-error match not correct in [%s] chain.", name)
+		}
+	}
+}
+
+func TestAs__Interface(t *testing.T) {
+	var es *errors.Error
+	if As(fmt.Errorf("outer: %w", Errorf("inner")), &es) != true {
+		t.Fatalf("Stack-wrapped error not found.")
+	} else if es.Error() != "inner" {
+		t.Fatalf("Stack-wrapped error not correct: [%s]", es.Error())
+	}
+
+	var x interface{ Unwrap() []error }
+	if As(Wrap(&multiError{}), &x) != true {
+		t.Fatalf("Error not found by interface.")
+	}
+}
+
+func TestAs__InvalidTarget(t *testing.T) {
+	var ce codeError
+
+	cases := map[string]interface{}{
+		"nil":         nil,
+		"non-pointer": ce,
+		"nil-pointer": (*codeError)(nil),
+		"non-error":   new(string),
+	}
+
+	for name, target := range cases {
+		func() {
+			defer func() {
+				if state := recover(); state == nil {
+					t.Fatalf("Expected panic for [%s] target.", name)
+				}
+			}()
+
+			As(os.ErrNotExist, target)
+		}()
+	}
+}
+
+func TestWrap__ReusesInnerStack(t *testing.T) {
+	inner := Errorf("inner error")
+	outer := fmt.Errorf("outer: %w", inner)
+
+	wrapped := Wrap(outer)
+
+	if wrapped.Error() != "outer: inner error" {
+		t.Fatalf("Message not correct: [%s]", wrapped.Error())
+	} else if wrapped.Err != outer {
+		t.Fatalf("Outer error not retained.")
+	} else if reflect.DeepEqual(wrapped.Callers(), inner.Callers()) != true {
+		t.Fatalf("Inner stack not reused.")
+	} else if Is(wrapped, inner) != true {
+		t.Fatalf("Inner error not found in the chain.")
+	}
+
+	errorfWrapped := Errorf("outer: %w", inner)
+
+	if reflect.DeepEqual(errorfWrapped.Callers(), inner.Callers()) != true {
+		t.Fatalf("Inner stack not reused by Errorf().")
+	}
+}
+
+func TestWrap__NewStack(t *testing.T) {
+	original := e.New("an error")
+	wrapped := Wrap(fmt.Errorf("outer: %w", original))
+
+	if strings.Contains(wrapped.ErrorStack(), "TestWrap__NewStack") != true {
+		t.Fatalf("Stack should start at the caller:\n%s", wrapped.ErrorStack())
+	}
+
+	// An inner error with a prefix can't carry a different message, so the
+	// outer one gets its own stack.
+
+	prefixed := errors.WrapPrefix(original, "prefix", 0)
+	outer := fmt.Errorf("outer: %w", prefixed)
+	wrapped = Wrap(outer)
+
+	if wrapped.Error() != outer.Error() {
+		t.Fatalf("Message not correct: [%s]", wrapped.Error())
+	} else if Is(wrapped, original) != true {
+		t.Fatalf("Original error not found in the chain.")
+	}
+}
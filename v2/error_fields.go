@@ -0,0 +1,110 @@
+package log
+
+const (
+	// ErrorCodeFieldName is the name of the field that carries the error code
+	// (see WithErrorCode) when an error is logged.
+	ErrorCodeFieldName = "error_code"
+)
+
+// fieldsError attaches fields and/or a code to an error without changing its
+// message.
+type fieldsError struct {
+	cause  error
+	fields Fields
+	code   string
+}
+
+// Error returns the message of the error that it wraps.
+func (fe *fieldsError) Error() string {
+	return fe.cause.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (fe *fieldsError) Unwrap() error {
+	return fe.cause
+}
+
+// WithErrorFields attaches structured fields to an error. The fields survive
+// further wrapping, can be read back with ErrorFields, and are added to the
+// fields of the message when the error is logged. The error keeps the stack
+// that was first captured for it (one is captured here if there isn't one
+// yet). Returns nil if the error is nil.
+func WithErrorFields(err error, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+
+	fe := &fieldsError{
+		cause:  wrap(err, 1),
+		fields: append(Fields(nil), fields...),
+	}
+
+	return wrap(fe, 1)
+}
+
+// WithErrorCode attaches a machine-readable code to an error. The code
+// survives further wrapping, can be read back with ErrorCode, and is added to
+// the fields of the message (as ErrorCodeFieldName) when the error is logged.
+// Returns nil if the error is nil.
+func WithErrorCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+
+	fe := &fieldsError{
+		cause: wrap(err, 1),
+		code:  code,
+	}
+
+	return wrap(fe, 1)
+}
+
+// ErrorFields returns the fields attached anywhere in the error's chain. If the
+// same name was attached more than once, the outermost value wins.
+func ErrorFields(err error) Fields {
+	attached := make([]*fieldsError, 0)
+
+	walkError(err, func(err error) bool {
+		if fe, ok := err.(*fieldsError); ok == true && len(fe.fields) > 0 {
+			attached = append(attached, fe)
+		}
+
+		return false
+	})
+
+	fields := make(Fields, 0)
+	for i := len(attached) - 1; i >= 0; i-- {
+		fields = fields.merge(attached[i].fields)
+	}
+
+	return fields
+}
+
+// ErrorCode returns the outermost code attached in the error's chain and
+// whether one was found.
+func ErrorCode(err error) (code string, found bool) {
+	walkError(err, func(err error) bool {
+		if fe, ok := err.(*fieldsError); ok == true && fe.code != "" {
+			code = fe.code
+			found = true
+
+			return true
+		}
+
+		return false
+	})
+
+	return code, found
+}
+
+// loggedErrorFields returns the fields and code attached to a logged error as
+// fields.
+func loggedErrorFields(err error) Fields {
+	fields := ErrorFields(err)
+
+	if code, found := ErrorCode(err); found == true {
+		fields = fields.merge(Fields{NewField(ErrorCodeFieldName, code)})
+	}
+
+	return fields
+}
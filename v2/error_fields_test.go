@@ -0,0 +1,112 @@
+package log
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+func TestWithErrorFields(t *testing.T) {
+	original := Errorf("an error happened")
+
+	err := WithErrorFields(original, NewField("user", "joe"), NewField("attempt", 1))
+	err = Annotate(fmt.Errorf("outer: %w", err), "while testing")
+	err = WithErrorFields(err, NewField("attempt", 2), NewField("request_id", "abc"))
+
+	if err.Error() != "while testing: outer: an error happened" {
+		t.Fatalf("Message should not change: [%s]", err.Error())
+	} else if reflect.DeepEqual(err.(*errors.Error).Callers(), original.Callers()) != true {
+		t.Fatalf("Original stack not kept.")
+	} else if Is(err, original) != true {
+		t.Fatalf("Original error not found in the chain.")
+	}
+
+	expected := Fields{
+		NewField("user", "joe"),
+		NewField("attempt", 2),
+		NewField("request_id", "abc"),
+	}
+
+	if fields := ErrorFields(Wrap(err)); reflect.DeepEqual(fields, expected) != true {
+		t.Fatalf("Fields not correct: %v", fields)
+	}
+}
+
+func TestWithErrorFields__Nil(t *testing.T) {
+	if err := WithErrorFields(nil, NewField("a", 1)); err != nil {
+		t.Fatalf("Nil error should not get fields: [%v]", err)
+	} else if err := WithErrorCode(nil, "code"); err != nil {
+		t.Fatalf("Nil error should not get a code: [%v]", err)
+	} else if fields := ErrorFields(e.New("an error happened")); len(fields) != 0 {
+		t.Fatalf("Error without fields should have no fields: %v", fields)
+	}
+}
+
+func TestWithErrorCode(t *testing.T) {
+	original := e.New("an error happened")
+
+	err := WithErrorCode(original, "inner_code")
+
+	if code, found := ErrorCode(err); found != true || code != "inner_code" {
+		t.Fatalf("Code not correct: [%s] %v", code, found)
+	} else if err.Error() != "an error happened" {
+		t.Fatalf("Message should not change: [%s]", err.Error())
+	} else if strings.Contains(err.(*errors.Error).ErrorStack(), "TestWithErrorCode") != true {
+		t.Fatalf("Stack not captured.")
+	}
+
+	err = WithErrorCode(Errorf("outer: %w", err), "outer_code")
+
+	if code, _ := ErrorCode(err); code != "outer_code" {
+		t.Fatalf("Outermost code should win: [%s]", code)
+	} else if _, found := ErrorCode(original); found != false {
+		t.Fatalf("Error without a code should have no code.")
+	}
+}
+
+func TestLogger_Errorf__ErrorFields(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	b := new(bytes.Buffer)
+	AddAdapter("json", NewJSONLogAdapter(b))
+
+	err := WithErrorFields(e.New("an error happened"), NewField("user", "joe"), NewField("attempt", 1))
+	err = WithErrorCode(err, "not_found")
+
+	l := NewLoggerWithAdapterName("fieldsTest", "test").With(NewField("tenant", 11))
+	l.ErrorFieldsf(nil, err, Fields{NewField("attempt", 3)}, "Error message")
+
+	expected := Fields{
+		NewField("tenant", 11),
+		NewField("user", "joe"),
+		NewField("attempt", 3),
+		NewField(ErrorCodeFieldName, "not_found"),
+	}
+
+	if reflect.DeepEqual(tla.lastContext.Fields(), expected) != true {
+		t.Fatalf("Fields not correct: %v", tla.lastContext.Fields())
+	}
+
+	l = NewLoggerWithAdapterName("fieldsTest", "json")
+	l.Errorf(nil, err, "Error message")
+
+	if strings.HasSuffix(b.String(), `,"user":"joe","attempt":1,"error_code":"not_found"}`+"\n") != true {
+		t.Fatalf("Error fields not emitted: [%s]", b.String())
+	}
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field is a single structured name/value pair that is carried alongside a
+// log message rather than being baked into the message text.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// NewField returns a new Field.
+func NewField(name string, value interface{}) Field {
+	return Field{
+		Name:  name,
+		Value: value,
+	}
+}
+
+// String returns the field as a "name=value" pair.
+func (f Field) String() string {
+	return fmt.Sprintf("%s=%v", f.Name, f.Value)
+}
+
+// Fields is an ordered list of fields.
+type Fields []Field
+
+// String returns the fields as space-separated "name=value" pairs.
+func (fields Fields) String() string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.String()
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Get returns the value of the field with the given name and whether it was
+// found.
+func (fields Fields) Get(name string) (value interface{}, found bool) {
+	for _, f := range fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// merge returns a new list with the given fields appended. A field whose name
+// is already present replaces the existing value in its original position.
+func (fields Fields) merge(other Fields) Fields {
+	if len(other) == 0 {
+		return fields
+	}
+
+	merged := make(Fields, len(fields), len(fields)+len(other))
+	copy(merged, fields)
+
+	for _, f := range other {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == f.Name {
+				merged[i].Value = f.Value
+				replaced = true
+
+				break
+			}
+		}
+
+		if replaced == false {
+			merged = append(merged, f)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestField_String(t *testing.T) {
+	f := NewField("abc", 123)
+	if f.String() != "abc=123" {
+		t.Fatalf("Field not rendered correctly: [%s]", f.String())
+	}
+}
+
+func TestFields_String(t *testing.T) {
+	fields := Fields{
+		NewField("abc", 123),
+		NewField("def", "ghi"),
+	}
+
+	if fields.String() != "abc=123 def=ghi" {
+		t.Fatalf("Fields not rendered correctly: [%s]", fields.String())
+	}
+}
+
+func TestFields_Get__hit(t *testing.T) {
+	fields := Fields{
+		NewField("abc", 123),
+	}
+
+	value, found := fields.Get("abc")
+	if found != true {
+		t.Fatalf("Field not found.")
+	} else if value != 123 {
+		t.Fatalf("Field value not correct: [%v]", value)
+	}
+}
+
+func TestFields_Get__miss(t *testing.T) {
+	fields := Fields{
+		NewField("abc", 123),
+	}
+
+	_, found := fields.Get("def")
+	if found != false {
+		t.Fatalf("Field should not have been found.")
+	}
+}
+
+func TestFields_merge(t *testing.T) {
+	original := Fields{
+		NewField("abc", 1),
+		NewField("def", 2),
+	}
+
+	merged := original.merge(Fields{NewField("ghi", 3), NewField("abc", 4)})
+
+	expected := Fields{
+		NewField("abc", 4),
+		NewField("def", 2),
+		NewField("ghi", 3),
+	}
+
+	if reflect.DeepEqual(merged, expected) != true {
+		t.Fatalf("Merge not correct: %v", merged)
+	}
+
+	if original[0].Value != 1 {
+		t.Fatalf("Original fields should not have been modified.")
+	}
+}
+
+func TestFields_merge__empty(t *testing.T) {
+	original := Fields{
+		NewField("abc", 1),
+	}
+
+	merged := original.merge(nil)
+	if reflect.DeepEqual(merged, original) != true {
+		t.Fatalf("Merge with nothing should be a no-op: %v", merged)
+	}
+}
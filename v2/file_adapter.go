@@ -0,0 +1,423 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	golog "log"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileLogAdapterConfig describes how a FileLogAdapter rotates its file.
+type FileLogAdapterConfig struct {
+	// MaxSize is the size (in bytes) that the file may grow to before it is
+	// rotated. Zero disables size-based rotation.
+	MaxSize int64
+
+	// Interval is the maximum amount of time that the file will be written to
+	// before it is rotated. Zero disables time-based rotation.
+	Interval time.Duration
+
+	// MaxBackups is the number of rotated files to keep. Rotated files are
+	// named by appending ".1" (the newest), ".2", etc.. to the file-path.
+	MaxBackups int
+
+	// Compress gzips rotated files (which then have an additional ".gz"
+	// suffix). Compression happens in the background so that logging isn't
+	// blocked by it.
+	Compress bool
+}
+
+// FileLogAdapter writes logging to a file, rotating it by size and/or age. The
+// file is also reopened when the process receives SIGHUP (where supported) so
+// that it cooperates with external rotation tools like logrotate.
+type FileLogAdapter struct {
+	filepath string
+	config   FileLogAdapterConfig
+
+	f        *os.File
+	size     int64
+	openedAt time.Time
+
+	logger       *golog.Logger
+	stopSignals  func()
+	nowFunc      func() time.Time
+	compressFunc func(sourceFilepath, destinationFilepath string) error
+	isClosed     bool
+
+	// compressions tracks the background compression of the newest backup.
+	// compressErr is the first compression error (returned by Close) and is
+	// only read after waiting on compressions. A backup that failed to
+	// compress is kept (see keepUncompressedBackup).
+	compressions sync.WaitGroup
+	compressErr  error
+
+	m sync.Mutex
+}
+
+// NewFileLogAdapter opens (or creates) the file and returns a new
+// FileLogAdapter. Call Close() when done with it.
+func NewFileLogAdapter(filepath string, config FileLogAdapterConfig) (fla *FileLogAdapter, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	fla = &FileLogAdapter{
+		filepath:     filepath,
+		config:       config,
+		nowFunc:      time.Now,
+		compressFunc: compressFile,
+	}
+
+	fla.logger = golog.New(fileLogAdapterWriter{fla: fla}, "", golog.LstdFlags)
+
+	err = fla.open()
+	PanicIf(err)
+
+	fla.stopSignals = fla.handleReopenSignals()
+
+	return fla, nil
+}
+
+// Debugf logs a debugging message.
+func (fla *FileLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return fla.logger.Output(1, *message)
+}
+
+// Infof logs an info message.
+func (fla *FileLogAdapter) Infof(lc *LogContext, message *string) error {
+	return fla.logger.Output(1, *message)
+}
+
+// Warningf logs a warning message.
+func (fla *FileLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return fla.logger.Output(1, *message)
+}
+
+// Errorf logs an error message.
+func (fla *FileLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return fla.logger.Output(1, *message)
+}
+
+// Reopen closes and reopens the file. This is what happens on SIGHUP and
+// should be called after the file has been moved by something else.
+func (fla *FileLogAdapter) Reopen() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	fla.m.Lock()
+	defer fla.m.Unlock()
+
+	// A SIGHUP might race with Close().
+	if fla.isClosed == true {
+		return nil
+	}
+
+	err = fla.close()
+	PanicIf(err)
+
+	err = fla.open()
+	PanicIf(err)
+
+	return nil
+}
+
+// Rotate rotates the file immediately.
+func (fla *FileLogAdapter) Rotate() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	fla.m.Lock()
+	defer fla.m.Unlock()
+
+	if fla.isClosed == true {
+		Panicf("file log-adapter is closed: [%s]", fla.filepath)
+	}
+
+	err = fla.rotate()
+	PanicIf(err)
+
+	return nil
+}
+
+// Close stops listening for signals, closes the file, and waits for any
+// background compression to finish. It returns the error of that compression,
+// if any.
+func (fla *FileLogAdapter) Close() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	fla.m.Lock()
+	defer fla.m.Unlock()
+
+	if fla.stopSignals != nil {
+		fla.stopSignals()
+		fla.stopSignals = nil
+	}
+
+	fla.isClosed = true
+
+	err = fla.close()
+	PanicIf(err)
+
+	fla.compressions.Wait()
+
+	err = fla.compressErr
+	fla.compressErr = nil
+
+	PanicIf(err)
+
+	return nil
+}
+
+// write writes a single, complete line, rotating first if necessary.
+func (fla *FileLogAdapter) write(p []byte) (n int, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	fla.m.Lock()
+	defer fla.m.Unlock()
+
+	if fla.f == nil {
+		Panicf("file log-adapter is closed: [%s]", fla.filepath)
+	}
+
+	if fla.shouldRotate(int64(len(p))) == true {
+		err := fla.rotate()
+		PanicIf(err)
+	}
+
+	n, err = fla.f.Write(p)
+	fla.size += int64(n)
+
+	PanicIf(err)
+
+	return n, nil
+}
+
+func (fla *FileLogAdapter) shouldRotate(writeSize int64) bool {
+	if fla.config.MaxSize > 0 && fla.size > 0 && fla.size+writeSize > fla.config.MaxSize {
+		return true
+	}
+
+	if fla.config.Interval > 0 && fla.nowFunc().Sub(fla.openedAt) >= fla.config.Interval {
+		return true
+	}
+
+	return false
+}
+
+func (fla *FileLogAdapter) open() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	f, err := os.OpenFile(fla.filepath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	PanicIf(err)
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		Panic(err)
+	}
+
+	fla.f = f
+	fla.size = fi.Size()
+	fla.openedAt = fla.nowFunc()
+
+	return nil
+}
+
+func (fla *FileLogAdapter) close() (err error) {
+	if fla.f == nil {
+		return nil
+	}
+
+	err = fla.f.Close()
+	fla.f = nil
+
+	return err
+}
+
+func (fla *FileLogAdapter) backupFilepath(n int) string {
+	filepath := fmt.Sprintf("%s.%d", fla.filepath, n)
+
+	if fla.config.Compress == true {
+		filepath += ".gz"
+	}
+
+	return filepath
+}
+
+// rotate closes the current file, shifts the backups, and opens a new file.
+// The newest backup is compressed in the background.
+func (fla *FileLogAdapter) rotate() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	err = fla.close()
+	PanicIf(err)
+
+	// The previous backup must be completely compressed before the backups
+	// are shifted. This only blocks if we rotate faster than we compress.
+	fla.compressions.Wait()
+
+	err = fla.keepUncompressedBackup()
+	PanicIf(err)
+
+	if fla.config.MaxBackups <= 0 {
+		err := os.Remove(fla.filepath)
+		if err != nil && os.IsNotExist(err) == false {
+			Panic(err)
+		}
+	} else {
+		err := os.Remove(fla.backupFilepath(fla.config.MaxBackups))
+		if err != nil && os.IsNotExist(err) == false {
+			Panic(err)
+		}
+
+		for i := fla.config.MaxBackups - 1; i >= 1; i-- {
+			err := os.Rename(fla.backupFilepath(i), fla.backupFilepath(i+1))
+			if err != nil && os.IsNotExist(err) == false {
+				Panic(err)
+			}
+		}
+
+		rotatedFilepath := fmt.Sprintf("%s.1", fla.filepath)
+
+		err = os.Rename(fla.filepath, rotatedFilepath)
+		if err != nil && os.IsNotExist(err) == false {
+			Panic(err)
+		}
+
+		if fla.config.Compress == true && err == nil {
+			compressedFilepath := fla.backupFilepath(1)
+
+			fla.compressions.Add(1)
+
+			go func() {
+				defer fla.compressions.Done()
+
+				err := fla.compressFunc(rotatedFilepath, compressedFilepath)
+				if err != nil && fla.compressErr == nil {
+					fla.compressErr = err
+				}
+			}()
+		}
+	}
+
+	err = fla.open()
+	PanicIf(err)
+
+	return nil
+}
+
+// keepUncompressedBackup moves the newest backup out of the way if its
+// compression failed (it's still uncompressed) so that the next rotation
+// doesn't replace it. It's kept with a timestamp suffix (e.g.
+// "app.log.1.20060102T150405.000000000") and is no longer rotated.
+func (fla *FileLogAdapter) keepUncompressedBackup() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	if fla.config.Compress == false || fla.config.MaxBackups <= 0 {
+		return nil
+	}
+
+	rotatedFilepath := fmt.Sprintf("%s.1", fla.filepath)
+
+	if _, err := os.Stat(rotatedFilepath); os.IsNotExist(err) == true {
+		return nil
+	} else if err != nil {
+		Panic(err)
+	}
+
+	keptFilepath := fmt.Sprintf("%s.%s", rotatedFilepath, fla.nowFunc().Format("20060102T150405.000000000"))
+
+	err = os.Rename(rotatedFilepath, keptFilepath)
+	PanicIf(err)
+
+	return nil
+}
+
+// compressFile gzips the source file to the destination file and removes the
+// source file.
+func compressFile(sourceFilepath, destinationFilepath string) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	source, err := os.Open(sourceFilepath)
+	PanicIf(err)
+
+	defer source.Close()
+
+	destination, err := os.OpenFile(destinationFilepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	PanicIf(err)
+
+	defer destination.Close()
+
+	// Don't leave a partial file behind if we fail.
+	isComplete := false
+
+	defer func() {
+		if isComplete == false {
+			destination.Close()
+			os.Remove(destinationFilepath)
+		}
+	}()
+
+	gw := gzip.NewWriter(destination)
+
+	_, err = io.Copy(gw, source)
+	PanicIf(err)
+
+	err = gw.Close()
+	PanicIf(err)
+
+	err = destination.Close()
+	PanicIf(err)
+
+	isComplete = true
+
+	err = os.Remove(sourceFilepath)
+	PanicIf(err)
+
+	return nil
+}
+
+// fileLogAdapterWriter exposes FileLogAdapter.write as an io.Writer without
+// making it part of FileLogAdapter's public interface.
+type fileLogAdapterWriter struct {
+	fla *FileLogAdapter
+}
+
+func (flaw fileLogAdapterWriter) Write(p []byte) (n int, err error) {
+	return flaw.fla.write(p)
+}
@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !illumos && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!illumos,!linux,!netbsd,!openbsd,!solaris
+
+package log
+
+// handleReopenSignals is a no-op on platforms without SIGHUP. Call Reopen()
+// directly instead.
+func (fla *FileLogAdapter) handleReopenSignals() func() {
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build aix || darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd illumos linux netbsd openbsd solaris
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handleReopenSignals reopens the file whenever SIGHUP is received. The
+// returned function stops the handling.
+func (fla *FileLogAdapter) handleReopenSignals() func() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := fla.Reopen(); err != nil {
+					PrintErrorf(err, "Could not reopen log file: [%s]", fla.filepath)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
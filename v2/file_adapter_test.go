@@ -0,0 +1,329 @@
+package log
+
+import (
+	"compress/gzip"
+	e "errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestFileLogger(t *testing.T, config FileLogAdapterConfig) (tempPath string, fla *FileLogAdapter, l *Logger, cleanup func()) {
+	cs := Snapshot()
+
+	tempPath, err := ioutil.TempDir("", "")
+	PanicIf(err)
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	fla, err = NewFileLogAdapter(path.Join(tempPath, "test.log"), config)
+	PanicIf(err)
+
+	AddAdapter("file", fla)
+
+	l = NewLoggerWithAdapterName("fileTest", "file")
+
+	cleanup = func() {
+		fla.Close()
+		os.RemoveAll(tempPath)
+		Restore(cs)
+	}
+
+	return tempPath, fla, l, cleanup
+}
+
+func readTestFile(filepath string) string {
+	data, err := ioutil.ReadFile(filepath)
+	PanicIf(err)
+
+	return string(data)
+}
+
+func TestFileLogAdapter(t *testing.T) {
+	tempPath, _, l, cleanup := newTestFileLogger(t, FileLogAdapterConfig{})
+	defer cleanup()
+
+	l.Infof(nil, "Info message")
+	l.Warningf(nil, "Warning message")
+
+	content := readTestFile(path.Join(tempPath, "test.log"))
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two lines: [%s]", content)
+	} else if strings.HasSuffix(lines[0], "fileTest: [INFO]  Info message") != true {
+		t.Fatalf("First line not correct: [%s]", lines[0])
+	} else if strings.HasSuffix(lines[1], "fileTest: [WARNING]  Warning message") != true {
+		t.Fatalf("Second line not correct: [%s]", lines[1])
+	}
+}
+
+func TestFileLogAdapter__RotateBySize(t *testing.T) {
+	config := FileLogAdapterConfig{
+		MaxSize:    100,
+		MaxBackups: 2,
+	}
+
+	tempPath, _, l, cleanup := newTestFileLogger(t, config)
+	defer cleanup()
+
+	for i := 0; i < 10; i++ {
+		l.Infof(nil, "Message (%d) with some padding to fill the file up", i)
+	}
+
+	filepath := path.Join(tempPath, "test.log")
+
+	if strings.Contains(readTestFile(filepath), "Message (9)") != true {
+		t.Fatalf("Current file does not have the last message.")
+	} else if strings.Contains(readTestFile(filepath+".1"), "Message (8)") != true {
+		t.Fatalf("First backup does not have the previous message.")
+	} else if strings.Contains(readTestFile(filepath+".2"), "Message (7)") != true {
+		t.Fatalf("Second backup does not have the message before that.")
+	}
+
+	if _, err := os.Stat(filepath + ".3"); os.IsNotExist(err) != true {
+		t.Fatalf("Too many backups were kept.")
+	}
+}
+
+func TestFileLogAdapter__RotateByInterval(t *testing.T) {
+	config := FileLogAdapterConfig{
+		Interval:   time.Hour,
+		MaxBackups: 1,
+	}
+
+	tempPath, fla, l, cleanup := newTestFileLogger(t, config)
+	defer cleanup()
+
+	now := time.Now()
+	fla.nowFunc = func() time.Time {
+		return now
+	}
+
+	l.Infof(nil, "First message")
+
+	now = now.Add(time.Minute)
+	l.Infof(nil, "Second message")
+
+	filepath := path.Join(tempPath, "test.log")
+
+	if _, err := os.Stat(filepath + ".1"); os.IsNotExist(err) != true {
+		t.Fatalf("File should not have been rotated yet.")
+	}
+
+	now = now.Add(time.Hour)
+	l.Infof(nil, "Third message")
+
+	backupContent := readTestFile(filepath + ".1")
+	if strings.Contains(backupContent, "First message") != true || strings.Contains(backupContent, "Second message") != true {
+		t.Fatalf("Backup not correct: [%s]", backupContent)
+	}
+
+	content := readTestFile(filepath)
+	if strings.Contains(content, "Third message") != true || strings.Contains(content, "First message") == true {
+		t.Fatalf("Current file not correct: [%s]", content)
+	}
+}
+
+func TestFileLogAdapter__Compress(t *testing.T) {
+	config := FileLogAdapterConfig{
+		MaxBackups: 1,
+		Compress:   true,
+	}
+
+	tempPath, fla, l, cleanup := newTestFileLogger(t, config)
+	defer cleanup()
+
+	l.Infof(nil, "Compressed message")
+
+	err := fla.Rotate()
+	PanicIf(err)
+
+	// Wait for the background compression.
+	err = fla.Close()
+	PanicIf(err)
+
+	filepath := path.Join(tempPath, "test.log")
+
+	if _, err := os.Stat(filepath + ".1"); os.IsNotExist(err) != true {
+		t.Fatalf("Uncompressed backup should have been removed.")
+	}
+
+	f, err := os.Open(filepath + ".1.gz")
+	PanicIf(err)
+
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	PanicIf(err)
+
+	data, err := ioutil.ReadAll(gr)
+	PanicIf(err)
+
+	if strings.Contains(string(data), "Compressed message") != true {
+		t.Fatalf("Compressed backup not correct: [%s]", string(data))
+	}
+}
+
+func readTestGzipFile(filepath string) string {
+	f, err := os.Open(filepath)
+	PanicIf(err)
+
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	PanicIf(err)
+
+	data, err := ioutil.ReadAll(gr)
+	PanicIf(err)
+
+	return string(data)
+}
+
+func TestFileLogAdapter__Compress__Consecutive(t *testing.T) {
+	config := FileLogAdapterConfig{
+		MaxBackups: 2,
+		Compress:   true,
+	}
+
+	tempPath, fla, l, cleanup := newTestFileLogger(t, config)
+	defer cleanup()
+
+	// The second rotation must wait for the first backup to be compressed
+	// before shifting it.
+
+	l.Infof(nil, "First message")
+
+	err := fla.Rotate()
+	PanicIf(err)
+
+	l.Infof(nil, "Second message")
+
+	err = fla.Rotate()
+	PanicIf(err)
+
+	l.Infof(nil, "Third message")
+
+	err = fla.Close()
+	PanicIf(err)
+
+	filepath := path.Join(tempPath, "test.log")
+
+	if content := readTestGzipFile(filepath + ".2.gz"); strings.Contains(content, "First message") != true {
+		t.Fatalf("Oldest backup not correct: [%s]", content)
+	} else if content := readTestGzipFile(filepath + ".1.gz"); strings.Contains(content, "Second message") != true {
+		t.Fatalf("Newest backup not correct: [%s]", content)
+	} else if content := readTestFile(filepath); strings.Contains(content, "Third message") != true {
+		t.Fatalf("Current file not correct: [%s]", content)
+	}
+
+	if _, err := os.Stat(filepath + ".1"); os.IsNotExist(err) != true {
+		t.Fatalf("Uncompressed backup should have been removed.")
+	}
+}
+
+func TestFileLogAdapter__Compress__Failed(t *testing.T) {
+	config := FileLogAdapterConfig{
+		MaxBackups: 2,
+		Compress:   true,
+	}
+
+	tempPath, fla, l, cleanup := newTestFileLogger(t, config)
+	defer cleanup()
+
+	filepath := path.Join(tempPath, "test.log")
+
+	// Fail the first compression.
+	isFailed := false
+	fla.compressFunc = func(sourceFilepath, destinationFilepath string) error {
+		if isFailed == false {
+			isFailed = true
+			return e.New("compression failed")
+		}
+
+		return compressFile(sourceFilepath, destinationFilepath)
+	}
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	fla.nowFunc = func() time.Time {
+		return now
+	}
+
+	l.Infof(nil, "First message")
+
+	err := fla.Rotate()
+	PanicIf(err)
+
+	l.Infof(nil, "Second message")
+
+	err = fla.Rotate()
+	PanicIf(err)
+
+	if err := fla.Close(); err == nil {
+		t.Fatalf("Expected the compression error from Close.")
+	}
+
+	keptFilepath := filepath + ".1.20200102T030405.000000006"
+
+	if content := readTestFile(keptFilepath); strings.Contains(content, "First message") != true {
+		t.Fatalf("Uncompressed backup not kept: [%s]", content)
+	} else if content := readTestGzipFile(filepath + ".1.gz"); strings.Contains(content, "Second message") != true {
+		t.Fatalf("Newest backup not correct: [%s]", content)
+	}
+
+	if _, err := os.Stat(filepath + ".1"); os.IsNotExist(err) != true {
+		t.Fatalf("Uncompressed backup should have been moved or compressed.")
+	}
+}
+
+func TestFileLogAdapter_Reopen(t *testing.T) {
+	tempPath, fla, l, cleanup := newTestFileLogger(t, FileLogAdapterConfig{})
+	defer cleanup()
+
+	l.Infof(nil, "Before move")
+
+	filepath := path.Join(tempPath, "test.log")
+	movedFilepath := path.Join(tempPath, "moved.log")
+
+	err := os.Rename(filepath, movedFilepath)
+	PanicIf(err)
+
+	err = fla.Reopen()
+	PanicIf(err)
+
+	l.Infof(nil, "After move")
+
+	if content := readTestFile(movedFilepath); strings.Contains(content, "After move") == true {
+		t.Fatalf("Moved file was written after reopen: [%s]", content)
+	}
+
+	if content := readTestFile(filepath); strings.Contains(content, "After move") != true {
+		t.Fatalf("New file not written after reopen: [%s]", content)
+	}
+}
+
+func TestFileLogAdapter_Close(t *testing.T) {
+	_, fla, _, cleanup := newTestFileLogger(t, FileLogAdapterConfig{})
+	defer cleanup()
+
+	err := fla.Close()
+	PanicIf(err)
+
+	message := "message"
+	if err := fla.Infof(nil, &message); err == nil {
+		t.Fatalf("Expected error writing to closed adapter.")
+	}
+
+	err = fla.Reopen()
+	PanicIf(err)
+
+	if err := fla.Infof(nil, &message); err == nil {
+		t.Fatalf("Reopen should not reopen a closed adapter.")
+	}
+}
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// GoroutineFunc is the function run by Logger.Go and Group.Go.
+type GoroutineFunc func(ctx context.Context) error
+
+// Go runs the function in a new goroutine. If it panics, the panic is
+// recovered and converted to a stack-wrapped error (see Recover). If it
+// panics or returns an error, the error is annotated with where the goroutine
+// was started (see Annotate) and logged at the error level with that location
+// as the caller.
+func (l *Logger) Go(ctx context.Context, cb GoroutineFunc) {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(2, pcs)
+
+	go func() {
+		if es := runGoroutine(ctx, pcs[0], cb); es != nil {
+			l.logGoroutineError(ctx, pcs[0], es)
+		}
+	}()
+}
+
+// runGoroutine runs the function and returns its error or panic, stack-wrapped
+// and annotated with the location that the goroutine was started from.
+func runGoroutine(ctx context.Context, spawnPC uintptr, cb GoroutineFunc) (es *errors.Error) {
+	defer func() {
+		if state := recover(); state != nil {
+			es = annotateGoroutine(recoveredError(state), spawnPC)
+		}
+	}()
+
+	if err := cb(ctx); err != nil {
+		return annotateGoroutine(err, spawnPC)
+	}
+
+	return nil
+}
+
+// annotateGoroutine annotates the error with the location that the goroutine
+// was started from.
+func annotateGoroutine(err error, spawnPC uintptr) *errors.Error {
+	ae := &annotatedError{
+		annotation: Annotation{
+			Message: "in goroutine",
+			Caller:  getCallerFromPC(spawnPC),
+		},
+		cause: wrap(err, 1),
+	}
+
+	return wrap(ae, 1)
+}
+
+var (
+	// goroutineFallbackWriter receives goroutine failures that could not be
+	// logged (e.g. because the adapter returned an error).
+	goroutineFallbackWriter io.Writer = os.Stderr
+)
+
+// logGoroutineError logs the error of a goroutine with the location that it
+// was started from as the caller. Logging happens in the goroutine, so a
+// failure to log must not panic there or it would take down the process.
+// Instead, it is reported along with the original failure to
+// goroutineFallbackWriter.
+func (l *Logger) logGoroutineError(ctx context.Context, spawnPC uintptr, es *errors.Error) {
+	defer func() {
+		if state := recover(); state != nil {
+			fmt.Fprintf(goroutineFallbackWriter, "could not log goroutine failure: %v\n%s\n", state, errorStack(es))
+		}
+	}()
+
+	ls := l.doConfigure(false)
+
+	cs := callerSource{
+		skip: 1,
+		pc:   spawnPC,
+	}
+
+	l.logFrom(ls, ctx, cs, LevelError, es, nil, "Goroutine failed.", nil)
+}
+
+// Group runs a set of goroutines through a Logger, like errgroup.Group. Every
+// goroutine that panics or returns an error is logged as with Logger.Go, and
+// the first error is returned by Wait.
+type Group struct {
+	l      *Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup returns a new Group along with the context that is passed to its
+// goroutines. That context is canceled when the first goroutine fails or when
+// Wait returns. A nil context is treated as context.Background().
+func (l *Logger) NewGroup(ctx context.Context) (g *Group, groupCtx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	groupCtx, cancel := context.WithCancel(ctx)
+
+	g = &Group{
+		l:      l,
+		ctx:    groupCtx,
+		cancel: cancel,
+	}
+
+	return g, groupCtx
+}
+
+// Go runs the function in a new goroutine of the group.
+func (g *Group) Go(cb GoroutineFunc) {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(2, pcs)
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		es := runGoroutine(g.ctx, pcs[0], cb)
+		if es == nil {
+			return
+		}
+
+		g.l.logGoroutineError(g.ctx, pcs[0], es)
+
+		g.errOnce.Do(func() {
+			g.err = es
+			g.cancel()
+		})
+	}()
+}
+
+// Wait waits for all of the goroutines of the group to finish and returns the
+// first error (or panic), if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	return g.err
+}
@@ -0,0 +1,308 @@
+package log
+
+import (
+	"bytes"
+	e "errors"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+func setupGoroutineTest() (cla *CaptureLogAdapter, l *Logger) {
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	SetCallerCapture(true)
+
+	ClearAdapters()
+
+	cla = NewCaptureLogAdapter()
+	AddAdapter("capture", cla)
+
+	l = NewLoggerWithAdapterName("goroutineTest", "capture")
+
+	return cla, l
+}
+
+// waitForEntries waits for the given number of messages to be captured.
+func waitForEntries(t *testing.T, cla *CaptureLogAdapter, count int) []CapturedEntry {
+	for i := 0; i < 500; i++ {
+		if cla.Len() >= count {
+			return cla.Entries()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Timed out waiting for (%d) messages: (%d)", count, cla.Len())
+	return nil
+}
+
+func TestLogger_Go__Panic(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cla, l := setupGoroutineTest()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "parent")
+
+	var panicLine int
+
+	spawnLine := currentLine() + 1
+	l.Go(ctx, func(ctx context.Context) error {
+		if ctx.Value(ctxKey{}) != "parent" {
+			return e.New("context not passed")
+		}
+
+		panicLine = currentLine() + 1
+		panic("something broke")
+	})
+
+	entries := waitForEntries(t, cla, 1)
+	ce := entries[0]
+
+	if ce.Level != LevelError {
+		t.Fatalf("Level not correct: (%d)", ce.Level)
+	} else if ce.Message != "Goroutine failed." {
+		t.Fatalf("Message not correct: [%s]", ce.Message)
+	} else if ce.Caller.Line != spawnLine || path.Base(ce.Caller.File) != "goroutine_test.go" {
+		t.Fatalf("Caller should be the spawn site: %v (%d)", ce.Caller, spawnLine)
+	}
+
+	var pve *PanicValueError
+	if As(ce.Error, &pve) != true {
+		t.Fatalf("Panic not converted: [%v]", ce.Error)
+	} else if ce.Error.Error() != "in goroutine: panic: something broke" {
+		t.Fatalf("Error not correct: [%s]", ce.Error.Error())
+	}
+
+	annotations := Annotations(ce.Error)
+	if len(annotations) != 1 || annotations[0].Caller.Line != spawnLine {
+		t.Fatalf("Spawn site not recorded: %v", annotations)
+	}
+
+	stack := ce.Error.(*errors.Error).StackFrames()
+	if len(stack) == 0 || stack[0].LineNumber != panicLine {
+		t.Fatalf("Goroutine stack should start at the panic:\n%s", ce.Error.(*errors.Error).ErrorStack())
+	}
+}
+
+func TestLogger_Go__Error(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cla, l := setupGoroutineTest()
+	original := e.New("an error happened")
+
+	l.Go(nil, func(ctx context.Context) error {
+		return original
+	})
+
+	entries := waitForEntries(t, cla, 1)
+
+	if Is(entries[0].Error, original) != true {
+		t.Fatalf("Returned error not logged: [%v]", entries[0].Error)
+	} else if strings.Contains(entries[0].Formatted, "Annotations:\n  in goroutine\n    at goroutine_test.go:") != true {
+		t.Fatalf("Spawn site not rendered:\n%s", entries[0].Formatted)
+	}
+}
+
+func TestLogger_Go__Success(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cla, l := setupGoroutineTest()
+	done := make(chan struct{})
+
+	l.Go(nil, func(ctx context.Context) error {
+		close(done)
+		return nil
+	})
+
+	<-done
+	time.Sleep(10 * time.Millisecond)
+
+	if cla.Len() != 0 {
+		t.Fatalf("Nothing should be logged for a successful goroutine: %v", cla.Entries())
+	}
+}
+
+func TestGroup(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cla, l := setupGoroutineTest()
+
+	g, groupCtx := l.NewGroup(nil)
+	original := e.New("an error happened")
+
+	g.Go(func(ctx context.Context) error {
+		return original
+	})
+
+	g.Go(func(ctx context.Context) error {
+		// Canceled by the first failure.
+		<-ctx.Done()
+		return nil
+	})
+
+	err := g.Wait()
+
+	if Is(err, original) != true {
+		t.Fatalf("First error not returned: [%v]", err)
+	} else if groupCtx.Err() == nil {
+		t.Fatalf("Group context should be canceled.")
+	} else if cla.Len() != 1 {
+		t.Fatalf("Failure not logged: (%d)", cla.Len())
+	}
+}
+
+func TestGroup__Panic(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cla, l := setupGoroutineTest()
+
+	g, _ := l.NewGroup(context.Background())
+
+	spawnLine := currentLine() + 1
+	g.Go(func(ctx context.Context) error {
+		var values []int
+		values[1] = 2
+
+		return nil
+	})
+
+	err := g.Wait()
+
+	if err == nil {
+		t.Fatalf("Panic not returned.")
+	} else if annotations := Annotations(err); len(annotations) != 1 || annotations[0].Caller.Line != spawnLine {
+		t.Fatalf("Spawn site not recorded: %v", annotations)
+	}
+
+	entries := cla.Entries()
+	if len(entries) != 1 || entries[0].Caller.Line != spawnLine {
+		t.Fatalf("Panic not logged from the spawn site: %v", entries)
+	}
+}
+
+func TestGroup__Success(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cla, l := setupGoroutineTest()
+
+	g, groupCtx := l.NewGroup(nil)
+
+	for i := 0; i < 5; i++ {
+		g.Go(func(ctx context.Context) error {
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("No error expected: [%v]", err)
+	} else if groupCtx.Err() == nil {
+		t.Fatalf("Group context should be canceled after Wait.")
+	} else if cla.Len() != 0 {
+		t.Fatalf("Nothing should be logged: (%d)", cla.Len())
+	}
+}
+
+// lockedBuffer is a buffer that can be written and read from different
+// goroutines.
+type lockedBuffer struct {
+	b bytes.Buffer
+	m sync.Mutex
+}
+
+func (lb *lockedBuffer) Write(p []byte) (int, error) {
+	lb.m.Lock()
+	defer lb.m.Unlock()
+
+	return lb.b.Write(p)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.m.Lock()
+	defer lb.m.Unlock()
+
+	return lb.b.String()
+}
+
+func TestLogger_Go__FailingAdapter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	originalWriter := goroutineFallbackWriter
+	defer func() {
+		goroutineFallbackWriter = originalWriter
+	}()
+
+	b := new(lockedBuffer)
+	goroutineFallbackWriter = b
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+	AddAdapter("failing", &failingLogAdapter{})
+
+	l := NewLoggerWithAdapterName("goroutineTest", "failing")
+
+	// Without the fallback, the failure to log would panic in the goroutine
+	// and take down the test process.
+
+	done := make(chan struct{})
+
+	l.Go(nil, func(ctx context.Context) error {
+		defer close(done)
+		return e.New("first failure")
+	})
+
+	<-done
+
+	g, _ := l.NewGroup(nil)
+
+	g.Go(func(ctx context.Context) error {
+		panic("second failure")
+	})
+
+	if err := g.Wait(); err == nil {
+		t.Fatalf("Group error not returned despite the logging failure.")
+	}
+
+	for i := 0; i < 500 && strings.Count(b.String(), "could not log goroutine failure") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	output := b.String()
+	if strings.Count(output, "could not log goroutine failure: adapter failure\n") != 2 {
+		t.Fatalf("Logging failures not reported:\n%s", output)
+	} else if strings.Contains(output, "in goroutine: first failure") != true {
+		t.Fatalf("Goroutine error not reported:\n%s", output)
+	} else if strings.Contains(output, "in goroutine: panic: second failure") != true {
+		t.Fatalf("Goroutine panic not reported:\n%s", output)
+	}
+}
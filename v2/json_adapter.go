@@ -0,0 +1,156 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Key names used by JSONLogAdapter. Fields whose names collide with one of
+// these are emitted with a "fields." prefix.
+const (
+	JSONKeyTime    = "time"
+	JSONKeyLevel   = "level"
+	JSONKeyNoun    = "noun"
+	JSONKeyMessage = "message"
+	JSONKeyError   = "error"
+	JSONKeyStack   = "stack"
+	JSONKeyCaller  = "caller"
+)
+
+const (
+	jsonFieldCollisionPrefix = "fields."
+)
+
+var (
+	jsonReservedKeys = map[string]struct{}{
+		JSONKeyTime:    {},
+		JSONKeyLevel:   {},
+		JSONKeyNoun:    {},
+		JSONKeyMessage: {},
+		JSONKeyError:   {},
+		JSONKeyStack:   {},
+		JSONKeyCaller:  {},
+	}
+)
+
+// JSONLogAdapter writes one JSON object per line to an io.Writer. The keys are
+// always written in the same order: time, level, noun, message, error, stack,
+// caller, and then any fields.
+type JSONLogAdapter struct {
+	w          io.Writer
+	timeLayout string
+
+	m sync.Mutex
+}
+
+// NewJSONLogAdapter returns a new JSONLogAdapter. Timestamps default to
+// RFC3339 with nanoseconds.
+func NewJSONLogAdapter(w io.Writer) *JSONLogAdapter {
+	return &JSONLogAdapter{
+		w:          w,
+		timeLayout: time.RFC3339Nano,
+	}
+}
+
+// SetTimeLayout sets the layout (as taken by time.Format) used to render the
+// timestamp.
+func (jla *JSONLogAdapter) SetTimeLayout(layout string) {
+	jla.m.Lock()
+	defer jla.m.Unlock()
+
+	jla.timeLayout = layout
+}
+
+// Debugf logs a debugging message.
+func (jla *JSONLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return jla.write(lc)
+}
+
+// Infof logs an info message.
+func (jla *JSONLogAdapter) Infof(lc *LogContext, message *string) error {
+	return jla.write(lc)
+}
+
+// Warningf logs a warning message.
+func (jla *JSONLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return jla.write(lc)
+}
+
+// Errorf logs an error message.
+func (jla *JSONLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return jla.write(lc)
+}
+
+func (jla *JSONLogAdapter) write(lc *LogContext) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	jla.m.Lock()
+	defer jla.m.Unlock()
+
+	b := new(bytes.Buffer)
+	b.WriteString("{")
+
+	writeJSONPair(b, JSONKeyTime, lc.Time().Format(jla.timeLayout), true)
+	writeJSONPair(b, JSONKeyLevel, string(lc.LevelName()), false)
+	writeJSONPair(b, JSONKeyNoun, lc.Noun(), false)
+	writeJSONPair(b, JSONKeyMessage, lc.Message(), false)
+
+	if lcErr := lc.Error(); lcErr != nil {
+		writeJSONPair(b, JSONKeyError, lcErr.Error(), false)
+		writeJSONPair(b, JSONKeyStack, lc.ErrorStack(), false)
+	}
+
+	if caller := lc.Caller(); caller.File != "" {
+		writeJSONPair(b, JSONKeyCaller, caller.String(), false)
+	}
+
+	for _, f := range lc.Fields() {
+		name := f.Name
+		if _, found := jsonReservedKeys[name]; found == true {
+			name = jsonFieldCollisionPrefix + name
+		}
+
+		writeJSONPair(b, name, f.Value, false)
+	}
+
+	b.WriteString("}\n")
+
+	_, err = jla.w.Write(b.Bytes())
+	PanicIf(err)
+
+	return nil
+}
+
+// writeJSONPair writes a single key/value pair. Values that can not be encoded
+// (and errors, which encode as empty objects) are written as strings.
+func writeJSONPair(b *bytes.Buffer, key string, value interface{}, isFirst bool) {
+	if isFirst == false {
+		b.WriteString(",")
+	}
+
+	encodedKey, err := json.Marshal(key)
+	PanicIf(err)
+
+	b.Write(encodedKey)
+	b.WriteString(":")
+
+	if valueErr, ok := value.(error); ok == true {
+		value = valueErr.Error()
+	}
+
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		encodedValue, err = json.Marshal(fmt.Sprintf("%v", value))
+		PanicIf(err)
+	}
+
+	b.Write(encodedValue)
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	e "errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLogAdapter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	jla := NewJSONLogAdapter(b)
+	AddAdapter("json", jla)
+
+	l := NewLoggerWithAdapterName("jsonTest", "json").With(NewField("request_id", "abc"))
+
+	l.InfoFieldsf(nil, Fields{NewField("count", 11), NewField("level", "x")}, "Info message: %s", "aa")
+
+	line := b.String()
+	if strings.HasSuffix(line, "\n") != true {
+		t.Fatalf("Entry not newline-terminated: [%s]", line)
+	}
+
+	entry := make(map[string]interface{})
+
+	err := json.Unmarshal([]byte(line), &entry)
+	PanicIf(err)
+
+	if entry[JSONKeyLevel] != "info" {
+		t.Fatalf("Level not correct: [%v]", entry[JSONKeyLevel])
+	} else if entry[JSONKeyNoun] != "jsonTest" {
+		t.Fatalf("Noun not correct: [%v]", entry[JSONKeyNoun])
+	} else if entry[JSONKeyMessage] != "Info message: aa" {
+		t.Fatalf("Message not correct: [%v]", entry[JSONKeyMessage])
+	} else if entry["request_id"] != "abc" {
+		t.Fatalf("Logger field not correct: [%v]", entry["request_id"])
+	} else if entry["count"] != float64(11) {
+		t.Fatalf("Call field not correct: [%v]", entry["count"])
+	} else if entry["fields.level"] != "x" {
+		t.Fatalf("Colliding field not correct: [%v]", entry["fields.level"])
+	}
+
+	if _, found := entry[JSONKeyError]; found == true {
+		t.Fatalf("Error should not be present.")
+	}
+
+	_, err = time.Parse(time.RFC3339Nano, entry[JSONKeyTime].(string))
+	PanicIf(err)
+
+	expectedPrefix := `{"time":`
+	if strings.HasPrefix(line, expectedPrefix) != true {
+		t.Fatalf("Keys not in a stable order: [%s]", line)
+	}
+
+	levelIndex := strings.Index(line, `"level":`)
+	nounIndex := strings.Index(line, `"noun":`)
+	messageIndex := strings.Index(line, `"message":`)
+	fieldIndex := strings.Index(line, `"request_id":`)
+
+	if (levelIndex < nounIndex && nounIndex < messageIndex && messageIndex < fieldIndex) != true {
+		t.Fatalf("Keys not in a stable order: [%s]", line)
+	}
+}
+
+func TestJSONLogAdapter__Error(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	jla := NewJSONLogAdapter(b)
+	AddAdapter("json", jla)
+
+	l := NewLoggerWithAdapterName("jsonTest", "json")
+
+	l.Errorf(nil, e.New("an error happened"), "Error message")
+
+	entry := make(map[string]interface{})
+
+	err := json.Unmarshal(b.Bytes(), &entry)
+	PanicIf(err)
+
+	if entry[JSONKeyLevel] != "error" {
+		t.Fatalf("Level not correct: [%v]", entry[JSONKeyLevel])
+	} else if entry[JSONKeyMessage] != "Error message" {
+		t.Fatalf("Message not correct: [%v]", entry[JSONKeyMessage])
+	} else if entry[JSONKeyError] != "an error happened" {
+		t.Fatalf("Error not correct: [%v]", entry[JSONKeyError])
+	} else if strings.Contains(entry[JSONKeyStack].(string), "json_adapter_test.go") != true {
+		t.Fatalf("Stack not correct: [%v]", entry[JSONKeyStack])
+	}
+}
+
+func TestJSONLogAdapter_SetTimeLayout(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	jla := NewJSONLogAdapter(b)
+	jla.SetTimeLayout("2006-01-02")
+	AddAdapter("json", jla)
+
+	l := NewLoggerWithAdapterName("jsonTest", "json")
+	l.Infof(nil, "Info message")
+
+	entry := make(map[string]interface{})
+
+	err := json.Unmarshal(b.Bytes(), &entry)
+	PanicIf(err)
+
+	_, err = time.Parse("2006-01-02", entry[JSONKeyTime].(string))
+	PanicIf(err)
+}
@@ -0,0 +1,132 @@
+package log
+
+import (
+	e "errors"
+	"fmt"
+	"strings"
+)
+
+// TraceLogAdapter is implemented by adapters that want trace messages
+// separately. Otherwise, trace messages are passed to Debugf().
+type TraceLogAdapter interface {
+	// Tracef logs a trace message.
+	Tracef(lc *LogContext, message *string) error
+}
+
+// CriticalLogAdapter is implemented by adapters that want critical messages
+// separately. Otherwise, critical messages are passed to Errorf().
+type CriticalLogAdapter interface {
+	// Criticalf logs a critical message.
+	Criticalf(lc *LogContext, message *string) error
+}
+
+// RegisterLevel registers an additional level with the given name and
+// severity. Once registered, the name can be used anywhere that a level-name
+// is configured (e.g. LogLevelName, LogExcludeBypassLevelName, and
+// LogNounLevels) and the level can be logged with Logger.Logf(). Adapters
+// receive the message via the method of the nearest built-in level at or
+// below it (e.g. a level between LevelWarning and LevelError goes to
+// Warningf()). Severities must not be negative, and neither the name nor the
+// severity may already be registered.
+func RegisterLevel(levelName LogLevelName, level LogLevel) {
+	levelName = LogLevelName(strings.ToLower(string(levelName)))
+
+	if levelName == "" {
+		Panic(e.New("level-name is empty"))
+	} else if level < 0 {
+		Panicf("level can not be negative: (%d)", level)
+	}
+
+	updateRegistry(func(r *registry) {
+		if _, found := r.levelsByName[levelName]; found == true {
+			Panicf("level-name already registered: [%s]", levelName)
+		}
+
+		if existingLevelName, found := r.levelNamesByLevel[level]; found == true {
+			Panicf("level already registered as [%s]: (%d)", existingLevelName, level)
+		}
+
+		r.levelsByName[levelName] = level
+		r.levelNamesByLevel[level] = levelName
+	})
+}
+
+// GetLevel returns the level with the given name (built-in or registered).
+func GetLevel(levelName LogLevelName) (level LogLevel, found bool) {
+	levelName = LogLevelName(strings.ToLower(string(levelName)))
+	level, found = loadRegistry().levelsByName[levelName]
+
+	return level, found
+}
+
+// GetLevelName returns the name of the given level (built-in or registered).
+func GetLevelName(level LogLevel) (levelName LogLevelName, found bool) {
+	levelName, found = loadRegistry().levelNamesByLevel[level]
+	return levelName, found
+}
+
+// String returns the name of the level or its number if not registered.
+func (level LogLevel) String() string {
+	if levelName, found := GetLevelName(level); found == true {
+		return string(levelName)
+	}
+
+	return fmt.Sprintf("level(%d)", int(level))
+}
+
+// adapterLevel returns the built-in level whose adapter method will receive
+// messages at the given level.
+func adapterLevel(level LogLevel) LogLevel {
+	if level >= LevelCritical {
+		return LevelCritical
+	} else if level >= LevelError {
+		return LevelError
+	} else if level >= LevelWarning {
+		return LevelWarning
+	} else if level >= LevelInfo {
+		return LevelInfo
+	} else if level >= LevelDebug {
+		return LevelDebug
+	}
+
+	return LevelTrace
+}
+
+// adapterMethod returns the adapter method that receives messages at the given
+// level.
+func adapterMethod(la LogAdapter, level LogLevel) logMethod {
+	switch adapterLevel(level) {
+	case LevelCritical:
+		if cla, ok := la.(CriticalLogAdapter); ok == true {
+			return cla.Criticalf
+		}
+
+		return la.Errorf
+	case LevelError:
+		return la.Errorf
+	case LevelWarning:
+		return la.Warningf
+	case LevelInfo:
+		return la.Infof
+	case LevelDebug:
+		return la.Debugf
+	}
+
+	if tla, ok := la.(TraceLogAdapter); ok == true {
+		return tla.Tracef
+	}
+
+	return la.Debugf
+}
+
+// messageLevel returns the level of the message that an adapter method
+// received. This is the level in the log-context if it's one that would have
+// been routed to that method (it might be a custom level) or the built-in
+// level of the method, otherwise.
+func messageLevel(lc *LogContext, methodLevel LogLevel) LogLevel {
+	if lc != nil && adapterLevel(lc.level) == methodLevel {
+		return lc.level
+	}
+
+	return methodLevel
+}
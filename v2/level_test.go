@@ -0,0 +1,279 @@
+package log
+
+import (
+	e "errors"
+	"testing"
+)
+
+type levelTestLogAdapter struct {
+	testLogAdapter
+
+	traceTriggered    bool
+	criticalTriggered bool
+}
+
+func (ltla *levelTestLogAdapter) Tracef(lc *LogContext, message *string) error {
+	ltla.traceTriggered = true
+	ltla.lastContext = lc
+	ltla.lastMessage = *message
+
+	return nil
+}
+
+func (ltla *levelTestLogAdapter) Criticalf(lc *LogContext, message *string) error {
+	ltla.criticalTriggered = true
+	ltla.lastContext = lc
+	ltla.lastMessage = *message
+
+	return nil
+}
+
+func unregisterTestLevel(levelName LogLevelName) {
+	updateRegistry(func(r *registry) {
+		level, found := r.levelsByName[levelName]
+		if found == false {
+			return
+		}
+
+		delete(r.levelsByName, levelName)
+		delete(r.levelNamesByLevel, level)
+	})
+}
+
+func TestLevel__Values(t *testing.T) {
+	// These are part of the public API (see the "Compatibility" note in the
+	// README). Changing them breaks anyone who stores levels as numbers.
+	expected := map[LogLevel]int{
+		LevelTrace:    0,
+		LevelDebug:    10,
+		LevelInfo:     20,
+		LevelWarning:  30,
+		LevelError:    40,
+		LevelCritical: 50,
+	}
+
+	for level, value := range expected {
+		if int(level) != value {
+			t.Fatalf("Level [%s] value not correct: (%d) != (%d)", level, int(level), value)
+		}
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	defer unregisterTestLevel("notice")
+
+	RegisterLevel("Notice", 25)
+
+	level, found := GetLevel("notice")
+	if found != true {
+		t.Fatalf("Level not registered.")
+	} else if level != 25 {
+		t.Fatalf("Level not correct: (%d)", level)
+	}
+
+	levelName, found := GetLevelName(25)
+	if found != true {
+		t.Fatalf("Level-name not registered.")
+	} else if levelName != "notice" {
+		t.Fatalf("Level-name not correct: [%s]", levelName)
+	}
+
+	if LogLevel(25).String() != "notice" {
+		t.Fatalf("Level string not correct: [%s]", LogLevel(25).String())
+	} else if LogLevel(26).String() != "level(26)" {
+		t.Fatalf("Unregistered level string not correct: [%s]", LogLevel(26).String())
+	}
+}
+
+func TestRegisterLevel__Duplicate(t *testing.T) {
+	defer unregisterTestLevel("notice")
+
+	RegisterLevel("notice", 25)
+
+	cases := map[LogLevelName]LogLevel{
+		"notice":  26,
+		"notice2": 25,
+		"info":    27,
+		"":        28,
+		"minus":   -1,
+	}
+
+	for levelName, level := range cases {
+		func() {
+			defer func() {
+				if state := recover(); state == nil {
+					t.Fatalf("Expected panic for [%s] (%d).", levelName, level)
+				}
+			}()
+
+			RegisterLevel(levelName, level)
+		}()
+	}
+
+	if _, found := GetLevel("notice2"); found != false {
+		t.Fatalf("Failed registration should not be published.")
+	}
+}
+
+func TestAdapterLevel(t *testing.T) {
+	cases := map[LogLevel]LogLevel{
+		LevelTrace:        LevelTrace,
+		LevelTrace + 5:    LevelTrace,
+		LevelDebug:        LevelDebug,
+		LevelInfo + 5:     LevelInfo,
+		LevelWarning:      LevelWarning,
+		LevelError + 9:    LevelError,
+		LevelCritical:     LevelCritical,
+		LevelCritical + 1: LevelCritical,
+	}
+
+	for level, expected := range cases {
+		if actual := adapterLevel(level); actual != expected {
+			t.Fatalf("Adapter level for (%d) not correct: (%d) != (%d)", level, actual, expected)
+		}
+	}
+}
+
+func TestLogger_Tracef__Fallback(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameTrace)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	l.Tracef(nil, "Trace message")
+
+	if tla.debugTriggered != true {
+		t.Fatalf("Trace message not passed to Debugf().")
+	} else if tla.lastContext.Level() != LevelTrace {
+		t.Fatalf("Level not correct: (%d)", tla.lastContext.Level())
+	} else if tla.lastMessage != "logTest: [TRACE]  Trace message" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+
+	err := e.New("an error happened")
+	l.Criticalf(nil, err, "Critical message")
+
+	if tla.errorTriggered != true {
+		t.Fatalf("Critical message not passed to Errorf().")
+	} else if tla.lastContext.LevelName() != levelNameCritical {
+		t.Fatalf("Level-name not correct: [%s]", tla.lastContext.LevelName())
+	}
+}
+
+func TestLogger_Tracef__Dedicated(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameTrace)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	ltla := new(levelTestLogAdapter)
+	AddAdapter("test", ltla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	l.Tracef(nil, "Trace message")
+
+	if ltla.traceTriggered != true {
+		t.Fatalf("Trace message not passed to Tracef().")
+	} else if ltla.debugTriggered != false {
+		t.Fatalf("Trace message should not be passed to Debugf().")
+	}
+
+	err := e.New("an error happened")
+	l.Criticalf(nil, err, "Critical message")
+
+	if ltla.criticalTriggered != true {
+		t.Fatalf("Critical message not passed to Criticalf().")
+	} else if ltla.errorTriggered != false {
+		t.Fatalf("Critical message should not be passed to Errorf().")
+	}
+}
+
+func TestLogger_Tracef__Filtered(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	ltla := new(levelTestLogAdapter)
+	AddAdapter("test", ltla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	l.Tracef(nil, "Trace message")
+
+	if ltla.traceTriggered != false {
+		t.Fatalf("Trace message should be filtered at the debug level.")
+	}
+}
+
+func TestLogger_Logf__CustomLevel(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	defer unregisterTestLevel("notice")
+
+	RegisterLevel("notice", LevelInfo+5)
+
+	tcp := newTestConfigurationProvider("notice")
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	l.Infof(nil, "Info message")
+
+	if tla.infoTriggered != false {
+		t.Fatalf("Info message should be filtered at the notice level.")
+	}
+
+	l.Logf(nil, LevelInfo+5, "Notice message")
+
+	if tla.infoTriggered != true {
+		t.Fatalf("Notice message not passed to Infof().")
+	} else if tla.lastContext.LevelName() != "notice" {
+		t.Fatalf("Level-name not correct: [%s]", tla.lastContext.LevelName())
+	} else if tla.lastMessage != "logTest: [NOTICE]  Notice message" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+}
+
+func TestMessageLevel(t *testing.T) {
+	lc := &LogContext{
+		level: LevelInfo + 5,
+	}
+
+	if level := messageLevel(lc, LevelInfo); level != LevelInfo+5 {
+		t.Fatalf("Custom level not returned: (%d)", level)
+	} else if level := messageLevel(lc, LevelWarning); level != LevelWarning {
+		t.Fatalf("Method level not returned: (%d)", level)
+	} else if level := messageLevel(nil, LevelDebug); level != LevelDebug {
+		t.Fatalf("Method level not returned for nil context: (%d)", level)
+	}
+}
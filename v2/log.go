@@ -4,8 +4,10 @@ import (
 	"bytes"
 	e "errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"text/template"
 
@@ -16,19 +18,28 @@ import (
 // LogLevel describes a log-level.
 type LogLevel int
 
-// Config severity integers.
+// Config severity integers. These are spaced apart so that custom levels can
+// be registered between them (see RegisterLevel). Note that this changed the
+// values of LevelDebug through LevelError (previously 0 through 3). Persist
+// and configure levels by name rather than by number.
 const (
-	// LevelDebug exposes debug logging an above. This exposes all logging.
-	LevelDebug LogLevel = iota
+	// LevelTrace exposes trace logging and above. This exposes all logging.
+	LevelTrace LogLevel = 0
+
+	// LevelDebug exposes debug logging an above.
+	LevelDebug LogLevel = 10
 
 	// LevelInfo exposes info logging and above.
-	LevelInfo LogLevel = iota
+	LevelInfo LogLevel = 20
 
 	// LevelWarning exposes warning logging and above.
-	LevelWarning LogLevel = iota
+	LevelWarning LogLevel = 30
+
+	// LevelError exposes error logging and above.
+	LevelError LogLevel = 40
 
-	// LevelError exposes error logging and above. This is the most restrictive.
-	LevelError LogLevel = iota
+	// LevelCritical exposes critical logging. This is the most restrictive.
+	LevelCritical LogLevel = 50
 )
 
 // Config severity names.
@@ -37,91 +48,134 @@ const (
 type LogLevelName string
 
 const (
-	levelNameDebug   LogLevelName = "debug"
-	levelNameInfo    LogLevelName = "info"
-	levelNameWarning LogLevelName = "warning"
-	levelNameError   LogLevelName = "error"
+	levelNameTrace    LogLevelName = "trace"
+	levelNameDebug    LogLevelName = "debug"
+	levelNameInfo     LogLevelName = "info"
+	levelNameWarning  LogLevelName = "warning"
+	levelNameError    LogLevelName = "error"
+	levelNameCritical LogLevelName = "critical"
 )
 
-// Seveirty name->integer map.
+// Seveirty name->integer map for the built-in levels. The effective maps,
+// which also include registered levels, are in the registry.
 var (
 	levelNameMap = map[LogLevelName]LogLevel{
-		levelNameDebug:   LevelDebug,
-		levelNameInfo:    LevelInfo,
-		levelNameWarning: LevelWarning,
-		levelNameError:   LevelError,
+		levelNameTrace:    LevelTrace,
+		levelNameDebug:    LevelDebug,
+		levelNameInfo:     LevelInfo,
+		levelNameWarning:  LevelWarning,
+		levelNameError:    LevelError,
+		levelNameCritical: LevelCritical,
 	}
 
 	levelNameMapR = map[LogLevel]LogLevelName{
-		LevelDebug:   levelNameDebug,
-		LevelInfo:    levelNameInfo,
-		LevelWarning: levelNameWarning,
-		LevelError:   levelNameError,
+		LevelTrace:    levelNameTrace,
+		LevelDebug:    levelNameDebug,
+		LevelInfo:     levelNameInfo,
+		LevelWarning:  levelNameWarning,
+		LevelError:    levelNameError,
+		LevelCritical: levelNameCritical,
 	}
 )
 
-// Other
-var (
-	includeFilters    = make(map[string]bool)
-	useIncludeFilters = false
-	excludeFilters    = make(map[string]bool)
-	useExcludeFilters = false
-
-	adapters = make(map[string]LogAdapter)
-
-	// TODO(dustin): !! Finish implementing this.
-	excludeBypassLevel LogLevel = -1
-)
-
-// AddIncludeFilter adds global include filter.
+// AddIncludeFilter adds global include filter. The noun may be a pattern where
+// "*" matches exactly one dot-separated segment and "**" matches any number of
+// segments (e.g. "storage.*", "*.cache", or "net.http.**").
 func AddIncludeFilter(noun string) {
-	includeFilters[noun] = true
-	useIncludeFilters = true
+	np := newNounPattern(noun)
+
+	updateRegistry(func(r *registry) {
+		r.includeFilters[noun] = np
+	})
 }
 
 // RemoveIncludeFilter removes global include filter.
 func RemoveIncludeFilter(noun string) {
-	delete(includeFilters, noun)
-	if len(includeFilters) == 0 {
-		useIncludeFilters = false
-	}
+	updateRegistry(func(r *registry) {
+		delete(r.includeFilters, noun)
+	})
 }
 
-// AddExcludeFilter adds global exclude filter.
+// AddExcludeFilter adds global exclude filter. The noun may be a pattern (see
+// AddIncludeFilter).
 func AddExcludeFilter(noun string) {
-	excludeFilters[noun] = true
-	useExcludeFilters = true
+	np := newNounPattern(noun)
+
+	updateRegistry(func(r *registry) {
+		r.excludeFilters[noun] = np
+	})
 }
 
 // RemoveExcludeFilter removes global exclude filter.
 func RemoveExcludeFilter(noun string) {
-	delete(excludeFilters, noun)
-	if len(excludeFilters) == 0 {
-		useExcludeFilters = false
-	}
+	updateRegistry(func(r *registry) {
+		delete(r.excludeFilters, noun)
+	})
 }
 
 // AddAdapter registers a new adapter.
 func AddAdapter(name string, la LogAdapter) {
-	if _, found := adapters[name]; found == true {
-		Panic(e.New("adapter already registered"))
-	}
-
 	if la == nil {
 		Panic(e.New("adapter is nil"))
 	}
 
-	adapters[name] = la
+	updateRegistry(func(r *registry) {
+		if _, found := r.adapters[name]; found == true {
+			Panic(e.New("adapter already registered"))
+		}
 
-	if GetDefaultAdapterName() == "" {
-		SetDefaultAdapterName(name)
-	}
+		r.adapters[name] = la
+
+		if r.defaultAdapterName == "" {
+			r.defaultAdapterName = name
+		}
+	})
 }
 
 // ClearAdapters deregisters all adapters.
 func ClearAdapters() {
-	adapters = make(map[string]LogAdapter)
-	SetDefaultAdapterName("")
+	updateRegistry(func(r *registry) {
+		r.adapters = make(map[string]LogAdapter)
+		r.defaultAdapterName = ""
+	})
+}
+
+var (
+	// builtinAdapterFactories create the adapters that can be used by name
+	// without being registered.
+	builtinAdapterFactories = map[string]func() LogAdapter{
+		ConsoleAdapterName:      NewConsoleLogAdapter,
+		ColorConsoleAdapterName: NewColorConsoleLogAdapter,
+	}
+
+	builtinAdapters      = make(map[string]LogAdapter)
+	builtinAdaptersMutex sync.Mutex
+)
+
+// findAdapter returns the registered adapter with the given name or, if there
+// isn't one, the built-in adapter with that name (which is created the first
+// time that it's needed).
+func findAdapter(r *registry, name string) (la LogAdapter, found bool) {
+	if la, found := r.adapters[name]; found == true {
+		return la, true
+	}
+
+	factory, found := builtinAdapterFactories[name]
+	if found == false {
+		return nil, false
+	}
+
+	builtinAdaptersMutex.Lock()
+	defer builtinAdaptersMutex.Unlock()
+
+	if la, found := builtinAdapters[name]; found == true {
+		return la, true
+	}
+
+	la = factory()
+	builtinAdapters[name] = la
+
+	return la, true
 }
 
 // LogAdapter describes minimal log-adapter functionality.
@@ -150,22 +204,127 @@ type MessageContext struct {
 	Noun          *string
 	Message       *string
 	ExcludeBypass bool
+	Fields        Fields
+
+	// Caller is the call-site of the logging call. It's empty unless caller
+	// capture is enabled (see SetCallerCapture).
+	Caller Caller
+
+	// Time is when the message was logged.
+	Time Timestamp
 }
 
 // LogContext encapsulates the current context for passing to the adapter.
 type LogContext struct {
-	logger *Logger
-	ctx    context.Context
+	logger        *Logger
+	ctx           context.Context
+	level         LogLevel
+	noun          string
+	message       string
+	err           *errors.Error
+	excludeBypass bool
+	fields        Fields
+	caller        Caller
+	time          Timestamp
+}
+
+// Logger returns the logger that produced the message.
+func (lc *LogContext) Logger() *Logger {
+	return lc.logger
+}
+
+// Context returns the context that was passed to the logging call. May be
+// nil.
+func (lc *LogContext) Context() context.Context {
+	return lc.ctx
+}
+
+// Level returns the level of the message.
+func (lc *LogContext) Level() LogLevel {
+	return lc.level
+}
+
+// LevelName returns the (lowercase) name of the level of the message.
+func (lc *LogContext) LevelName() LogLevelName {
+	return loadRegistry().levelNamesByLevel[lc.level]
+}
+
+// Noun returns the noun of the logger that produced the message.
+func (lc *LogContext) Noun() string {
+	return lc.noun
+}
+
+// Message returns the string-substituted message before it was passed through
+// the format template and without any error stack.
+func (lc *LogContext) Message() string {
+	return lc.message
+}
+
+// Error returns the error that was logged with the message, if any.
+func (lc *LogContext) Error() error {
+	if lc.err == nil {
+		return nil
+	}
+
+	return lc.err
+}
+
+// ErrorStack returns the stack of the error that was logged with the message
+// along with any annotations (see Annotate). Empty if there was no error.
+func (lc *LogContext) ErrorStack() string {
+	if lc.err == nil {
+		return ""
+	}
+
+	return errorStack(lc.err)
+}
+
+// ExcludeBypass returns whether the message was logged in spite of its noun
+// being excluded because its level met the exclude-bypass level.
+func (lc *LogContext) ExcludeBypass() bool {
+	return lc.excludeBypass
+}
+
+// Fields returns the structured fields for the message: those bound to the
+// logger with With() followed by those given to the call itself.
+func (lc *LogContext) Fields() Fields {
+	return lc.fields
+}
+
+// Caller returns the call-site of the logging call. It's empty (its File is
+// empty) unless caller capture is enabled (see SetCallerCapture).
+func (lc *LogContext) Caller() Caller {
+	return lc.caller
+}
+
+// Time returns when the message was logged. This is taken once per message
+// from the clock (see SetClock) and is the same time given to the template.
+func (lc *LogContext) Time() Timestamp {
+	return lc.time
 }
 
 // Logger is the main logger type.
 type Logger struct {
-	isConfigured bool
-	an           string
-	la           LogAdapter
-	t            *template.Template
-	systemLevel  LogLevel
-	noun         string
+	// an is the adapter-name that was requested. If empty, the default
+	// adapter-name is resolved whenever the logger is (re)configured.
+	an     string
+	noun   string
+	fields Fields
+
+	// state holds the current *loggerState.
+	state atomic.Value
+}
+
+// loggerState is the configuration that a logger resolved from the global
+// configuration. It is replaced, never modified, whenever the global
+// configuration changes.
+type loggerState struct {
+	registry           *registry
+	an                 string
+	la                 LogAdapter
+	t                  *template.Template
+	systemLevel        LogLevel
+	excludeBypassLevel LogLevel
 }
 
 // NewLoggerWithAdapterName initializes a logger struct to log to a specific
@@ -186,6 +345,16 @@ func NewLogger(noun string) (l *Logger) {
 	return l
 }
 
+// With returns a child logger with the same noun and adapter that attaches the
+// given fields to every message. Fields with a name that is already bound
+// replace the existing value.
+func (l *Logger) With(fields ...Field) *Logger {
+	child := NewLoggerWithAdapterName(l.noun, l.an)
+	child.fields = l.fields.merge(fields)
+
+	return child
+}
+
 // Noun returns the noun that this logger represents.
 func (l *Logger) Noun() string {
 	return l.noun
@@ -193,72 +362,131 @@ func (l *Logger) Noun() string {
 
 // Adapter returns the adapter used by this logger struct.
 func (l *Logger) Adapter() LogAdapter {
-	return l.la
+	ls := l.loadState()
+	if ls == nil {
+		return nil
+	}
+
+	return ls.la
+}
+
+func (l *Logger) loadState() *loggerState {
+	ls, _ := l.state.Load().(*loggerState)
+	return ls
 }
 
 var (
 	configureMutex sync.Mutex
+
+	// The most-recently parsed template. Templates are safe to share between
+	// loggers and are only parsed again when the format changes.
+	cachedFormat               string
+	cachedTemplateFuncsVersion uint64
+	cachedTemplate             *template.Template
 )
 
-func (l *Logger) doConfigure(force bool) {
+// doConfigure resolves the logger's adapter, level, and template from the
+// global configuration. This is a no-op unless this is the first time, the
+// global configuration has changed since the last time, or force is true.
+func (l *Logger) doConfigure(force bool) *loggerState {
+	r := loadRegistry()
+
+	if force == false {
+		if ls := l.loadState(); ls != nil && ls.registry.generation == r.generation {
+			return ls
+		}
+	}
+
 	configureMutex.Lock()
 	defer configureMutex.Unlock()
 
-	if l.isConfigured == true && force == false {
-		return
+	// Another goroutine may have configured us while we were waiting.
+	if force == false {
+		if ls := l.loadState(); ls != nil && ls.registry.generation == r.generation {
+			return ls
+		}
 	}
 
-	if IsConfigurationLoaded() == false {
+	if r.configurationLoaded == false {
 		Panic(e.New("can not configure because configuration is not loaded"))
 	}
 
-	if l.an == "" {
-		l.an = GetDefaultAdapterName()
+	ls := &loggerState{
+		registry: r,
+		an:       l.an,
+	}
+
+	if ls.an == "" {
+		ls.an = r.defaultAdapterName
 	}
 
 	// If this is empty, then no specific adapter was given or no system
 	// default was configured (which implies that no adapters were registered).
 	// All of our logging will be skipped.
-	if l.an != "" {
-		la, found := adapters[l.an]
+	if ls.an != "" {
+		la, found := findAdapter(r, ls.an)
 		if found == false {
-			Panic(fmt.Errorf("adapter is not valid: %s", l.an))
+			Panic(fmt.Errorf("adapter is not valid: %s", ls.an))
 		}
 
-		l.la = la
+		ls.la = la
 	}
 
 	// Set the level.
 
-	systemLevel, found := levelNameMap[levelName]
+	systemLevel, found := r.levelsByName[r.levelName]
 	if found == false {
-		Panic(fmt.Errorf("log-level not valid: [%s]", levelName))
+		Panic(fmt.Errorf("log-level not valid: [%s]", r.levelName))
 	}
 
-	l.systemLevel = systemLevel
+	configuredNounLevels, err := parseNounLevels(r.nounLevels, r.levelsByName)
+	PanicIf(err)
+
+	if nounLevel, found := findNounLevel(l.noun, configuredNounLevels, r.nounLevelOverrides); found == true {
+		systemLevel = nounLevel
+	}
+
+	ls.systemLevel = systemLevel
+
+	// Set the level at which excluded nouns are shown anyway.
+
+	ls.excludeBypassLevel = -1
+
+	if r.excludeBypassLevelName != "" {
+		excludeBypassLevelName := LogLevelName(strings.ToLower(string(r.excludeBypassLevelName)))
+
+		if ls.excludeBypassLevel, found = r.levelsByName[excludeBypassLevelName]; found == false {
+			Panic(fmt.Errorf("exclude bypass-level not valid: [%s]", r.excludeBypassLevelName))
+		}
+	}
 
 	// Set the form.
 
-	if format == "" {
+	if r.format == "" {
 		Panic(e.New("format is empty"))
 	}
 
-	if t, err := template.New("logItem").Parse(format); err != nil {
-		Panic(err)
-	} else {
-		l.t = t
+	if cachedTemplate == nil || cachedFormat != r.format || cachedTemplateFuncsVersion != r.templateFuncsVersion {
+		t, err := template.New("logItem").Funcs(r.templateFuncs).Parse(r.format)
+		PanicIf(err)
+
+		cachedFormat = r.format
+		cachedTemplateFuncsVersion = r.templateFuncsVersion
+		cachedTemplate = t
 	}
 
-	l.isConfigured = true
-}
+	ls.t = cachedTemplate
+
+	l.state.Store(ls)
 
-func (l *Logger) flattenMessage(lc *MessageContext, format *string, args []interface{}) (string, error) {
-	m := fmt.Sprintf(*format, args...)
+	return ls
+}
 
-	lc.Message = &m
+func (l *Logger) flattenMessage(t *template.Template, lc *MessageContext, message string) (string, error) {
+	lc.Message = &message
 
 	var b bytes.Buffer
-	if err := l.t.Execute(&b, *lc); err != nil {
+	if err := t.Execute(&b, *lc); err != nil {
 		return "", err
 	}
 
@@ -266,34 +494,62 @@ func (l *Logger) flattenMessage(lc *MessageContext, format *string, args []inter
 }
 
 func (l *Logger) allowMessage(noun string, level LogLevel) bool {
-	if _, found := includeFilters[noun]; found == true {
-		return true
+	r := loadRegistry()
+
+	includeSpecificity, includeFound := findNounMatchIn(noun, r.includeFilters, r.configIncludeFilters)
+	excludeSpecificity, excludeFound := findNounMatchIn(noun, r.excludeFilters, r.configExcludeFilters)
+
+	// If both an include and an exclude filter match, the most specific one
+	// wins. If they're equally specific, the include wins.
+	if includeFound == true {
+		if excludeFound == false || excludeSpecificity.isMoreSpecificThan(includeSpecificity) == false {
+			return true
+		}
+
+		return false
 	}
 
 	// If we didn't hit an include filter and we *had* include filters, filter
 	// it out.
-	if useIncludeFilters == true {
+	if len(r.includeFilters) > 0 || len(r.configIncludeFilters) > 0 {
 		return false
 	}
 
-	if _, found := excludeFilters[noun]; found == true {
+	if excludeFound == true {
 		return false
 	}
 
 	return true
 }
 
-func (l *Logger) makeLogContext(ctx context.Context) *LogContext {
-	return &LogContext{
-		ctx:    ctx,
-		logger: l,
+type logMethod func(lc *LogContext, message *string) error
+
+// callerSource describes where to find the call-site of a message: either skip
+// frames above the function that received it or at the given program-counter.
+type callerSource struct {
+	skip int
+	pc   uintptr
+}
+
+// log formats and forwards a message to the adapter. If err is given, it must
+// already be stack-wrapped and its stack will be appended to the message. skip
+// is the number of frames between the caller of the public logging method and
+// log().
+func (l *Logger) log(ls *loggerState, ctx context.Context, skip int, level LogLevel, err *errors.Error, fields Fields, format string, args []interface{}) error {
+	cs := callerSource{
+		skip: skip + 1,
 	}
+
+	return l.logFrom(ls, ctx, cs, level, err, fields, format, args)
 }
 
-type logMethod func(lc *LogContext, message *string) error
+// logFrom is log() with an explicit caller-source.
+func (l *Logger) logFrom(ls *loggerState, ctx context.Context, cs callerSource, level LogLevel, err *errors.Error, fields Fields, format string, args []interface{}) error {
+	if ls.la == nil {
+		return nil
+	}
 
-func (l *Logger) log(ctx context.Context, level LogLevel, lm logMethod, format string, args []interface{}) error {
-	if l.systemLevel > level {
+	if ls.systemLevel > level {
 		return nil
 	}
 
@@ -302,7 +558,7 @@ func (l *Logger) log(ctx context.Context, level LogLevel, lm logMethod, format s
 	//
 	// Notice that this is only relevant if the system-log level is letting
 	// *anything* show logs at the level we came in with.
-	canExcludeBypass := level >= excludeBypassLevel && excludeBypassLevel != -1
+	canExcludeBypass := level >= ls.excludeBypassLevel && ls.excludeBypassLevel != -1
 	didExcludeBypass := false
 
 	n := l.Noun()
@@ -315,83 +571,177 @@ func (l *Logger) log(ctx context.Context, level LogLevel, lm logMethod, format s
 		didExcludeBypass = true
 	}
 
-	levelName, found := levelNameMapR[level]
+	levelName, found := ls.registry.levelNamesByLevel[level]
 	if found == false {
 		Panicf("level not valid: (%d)", level)
 	}
 
 	levelName = LogLevelName(strings.ToUpper(string(levelName)))
 
+	if err != nil {
+		fields = l.fields.merge(loggedErrorFields(err)).merge(fields)
+	} else {
+		fields = l.fields.merge(fields)
+	}
+
+	var caller Caller
+	if ls.registry.captureCaller == true {
+		if cs.pc != 0 {
+			caller = getCallerFromPC(cs.pc)
+		} else {
+			caller = getCaller(cs.skip + 1)
+		}
+	}
+
+	ts := Timestamp{
+		Time:  ls.registry.clock.Now(),
+		start: ls.registry.clockStart,
+	}
+
 	mc := &MessageContext{
 		Level:         &levelName,
 		Noun:          &n,
 		ExcludeBypass: didExcludeBypass,
+		Fields:        fields,
+		Caller:        caller,
+		Time:          ts,
 	}
 
-	s, err := l.flattenMessage(mc, &format, args)
-	PanicIf(err)
+	// Format once. Arguments may have expensive (or non-idempotent) String()
+	// or Error() methods.
+	message := fmt.Sprintf(format, args...)
 
-	lc := l.makeLogContext(ctx)
+	lc := &LogContext{
+		logger:        l,
+		ctx:           ctx,
+		level:         level,
+		noun:          n,
+		message:       message,
+		err:           err,
+		excludeBypass: didExcludeBypass,
+		fields:        fields,
+		caller:        caller,
+		time:          ts,
+	}
 
-	err = lm(lc, &s)
-	PanicIf(err)
+	fullMessage := message
+	if err != nil {
+		fullMessage = l.mergeStack(err, format, message)
+	}
+
+	s, flattenErr := l.flattenMessage(ls.t, mc, fullMessage)
+	PanicIf(flattenErr)
+
+	lm := adapterMethod(ls.la, level)
 
-	if level == LevelError {
+	adapterErr := lm(lc, &s)
+	PanicIf(adapterErr)
+
+	if level >= LevelError {
 		return e.New(s)
 	}
 
 	return nil
 }
 
-func (l *Logger) mergeStack(err interface{}, format string, args []interface{}) (string, []interface{}) {
-	if format != "" {
-		format += "\n%s"
-	} else {
-		format = "%s"
+// mergeStack appends the stack of the error to the already-formatted message.
+// Without a format, the message is just the stack.
+func (l *Logger) mergeStack(err *errors.Error, format string, message string) string {
+	if format == "" {
+		return errorStack(err)
 	}
 
-	var stackified *errors.Error
-	stackified, ok := err.(*errors.Error)
-	if ok == false {
-		stackified = errors.Wrap(err, 2)
-	}
+	return message + "\n" + errorStack(err)
+}
 
-	args = append(args, stackified.ErrorStack())
+// Tracef forwards trace-logging to the underlying adapter.
+func (l *Logger) Tracef(ctx context.Context, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelTrace, nil, nil, format, args)
+}
 
-	return format, args
+// TraceFieldsf forwards trace-logging to the underlying adapter along with
+// the given structured fields.
+func (l *Logger) TraceFieldsf(ctx context.Context, fields Fields, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelTrace, nil, fields, format, args)
 }
 
 // Debugf forwards debug-logging to the underlying adapter.
 func (l *Logger) Debugf(ctx context.Context, format string, args ...interface{}) {
-	l.doConfigure(false)
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelDebug, nil, nil, format, args)
+}
 
-	if l.la != nil {
-		l.log(ctx, LevelDebug, l.la.Debugf, format, args)
-	}
+// DebugFieldsf forwards debug-logging to the underlying adapter along with
+// the given structured fields.
+func (l *Logger) DebugFieldsf(ctx context.Context, fields Fields, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelDebug, nil, fields, format, args)
 }
 
 // Infof forwards debug-logging to the underlying adapter.
 func (l *Logger) Infof(ctx context.Context, format string, args ...interface{}) {
-	l.doConfigure(false)
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelInfo, nil, nil, format, args)
+}
 
-	if l.la != nil {
-		l.log(ctx, LevelInfo, l.la.Infof, format, args)
-	}
+// InfoFieldsf forwards info-logging to the underlying adapter along with the
+// given structured fields.
+func (l *Logger) InfoFieldsf(ctx context.Context, fields Fields, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelInfo, nil, fields, format, args)
 }
 
 // Warningf forwards debug-logging to the underlying adapter.
 func (l *Logger) Warningf(ctx context.Context, format string, args ...interface{}) {
-	l.doConfigure(false)
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelWarning, nil, nil, format, args)
+}
 
-	if l.la != nil {
-		l.log(ctx, LevelWarning, l.la.Warningf, format, args)
-	}
+// WarningFieldsf forwards warning-logging to the underlying adapter along with
+// the given structured fields.
+func (l *Logger) WarningFieldsf(ctx context.Context, fields Fields, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, LevelWarning, nil, fields, format, args)
 }
 
 // Errorf forwards debug-logging to the underlying adapter.
 func (l *Logger) Errorf(ctx context.Context, errRaw interface{}, format string, args ...interface{}) {
-	l.doConfigure(false)
+	var err interface{}
+
+	if errRaw != nil {
+		_, ok := errRaw.(*errors.Error)
+		if ok == true {
+			err = errRaw
+		} else {
+			err = wrap(errRaw, 1)
+		}
+	}
+
+	l.errorf(ctx, LevelError, err, nil, format, args)
+}
+
+// ErrorFieldsf forwards error-logging to the underlying adapter along with the
+// given structured fields.
+func (l *Logger) ErrorFieldsf(ctx context.Context, errRaw interface{}, fields Fields, format string, args ...interface{}) {
+	var err interface{}
 
+	if errRaw != nil {
+		_, ok := errRaw.(*errors.Error)
+		if ok == true {
+			err = errRaw
+		} else {
+			err = wrap(errRaw, 1)
+		}
+	}
+
+	l.errorf(ctx, LevelError, err, fields, format, args)
+}
+
+// Criticalf forwards critical-logging to the underlying adapter. errRaw may be
+// nil.
+func (l *Logger) Criticalf(ctx context.Context, errRaw interface{}, format string, args ...interface{}) {
 	var err interface{}
 
 	if errRaw != nil {
@@ -399,17 +749,55 @@ func (l *Logger) Errorf(ctx context.Context, errRaw interface{}, format string,
 		if ok == true {
 			err = errRaw
 		} else {
-			err = errors.Wrap(errRaw, 1)
+			err = wrap(errRaw, 1)
 		}
 	}
 
-	if l.la != nil {
-		if errRaw != nil {
-			format, args = l.mergeStack(err, format, args)
+	l.errorf(ctx, LevelCritical, err, nil, format, args)
+}
+
+// CriticalFieldsf forwards critical-logging to the underlying adapter along
+// with the given structured fields.
+func (l *Logger) CriticalFieldsf(ctx context.Context, errRaw interface{}, fields Fields, format string, args ...interface{}) {
+	var err interface{}
+
+	if errRaw != nil {
+		_, ok := errRaw.(*errors.Error)
+		if ok == true {
+			err = errRaw
+		} else {
+			err = wrap(errRaw, 1)
 		}
+	}
+
+	l.errorf(ctx, LevelCritical, err, fields, format, args)
+}
 
-		l.log(ctx, LevelError, l.la.Errorf, format, args)
+// errorf logs an error message. The error, if given, must already be
+// stack-wrapped.
+func (l *Logger) errorf(ctx context.Context, level LogLevel, err interface{}, fields Fields, format string, args []interface{}) {
+	ls := l.doConfigure(false)
+
+	var stackified *errors.Error
+	if err != nil {
+		stackified = err.(*errors.Error)
 	}
+
+	l.log(ls, ctx, 2, level, stackified, fields, format, args)
+}
+
+// Logf forwards logging at any level, including those registered with
+// RegisterLevel, to the underlying adapter.
+func (l *Logger) Logf(ctx context.Context, level LogLevel, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, level, nil, nil, format, args)
+}
+
+// LogFieldsf forwards logging at any level to the underlying adapter along
+// with the given structured fields.
+func (l *Logger) LogFieldsf(ctx context.Context, level LogLevel, fields Fields, format string, args ...interface{}) {
+	ls := l.doConfigure(false)
+	l.log(ls, ctx, 1, level, nil, fields, format, args)
 }
 
 // ErrorIff logs a string-substituted message if errRaw is non-nil.
@@ -424,28 +812,36 @@ func (l *Logger) ErrorIff(ctx context.Context, errRaw interface{}, format string
 	if ok == true {
 		err = errRaw
 	} else {
-		err = errors.Wrap(errRaw, 1)
+		err = wrap(errRaw, 1)
 	}
 
-	l.Errorf(ctx, err, format, args...)
+	l.errorf(ctx, LevelError, err, nil, format, args)
 }
 
 // Panicf logs a string-substituted message.
 func (l *Logger) Panicf(ctx context.Context, errRaw interface{}, format string, args ...interface{}) {
-	l.doConfigure(false)
-
 	var wrapped interface{}
 
 	_, ok := errRaw.(*errors.Error)
 	if ok == true {
 		wrapped = errRaw
 	} else {
-		wrapped = errors.Wrap(errRaw, 1)
+		wrapped = wrap(errRaw, 1)
 	}
 
-	if l.la != nil {
-		format, args = l.mergeStack(wrapped, format, args)
-		wrapped = l.log(ctx, LevelError, l.la.Errorf, format, args)
+	l.panicf(ctx, wrapped, format, args)
+}
+
+// panicf logs a message and panics. The error must already be stack-wrapped.
+func (l *Logger) panicf(ctx context.Context, wrapped interface{}, format string, args []interface{}) {
+	ls := l.doConfigure(false)
+
+	if ls.la != nil {
+		// Nothing is returned if the message was filtered. Panic with the
+		// original error in that case.
+		if logged := l.log(ls, ctx, 2, LevelError, wrapped.(*errors.Error), nil, format, args); logged != nil {
+			wrapped = logged
+		}
 	}
 
 	Panic(wrapped)
@@ -459,7 +855,8 @@ func (l *Logger) PanicIff(ctx context.Context, errRaw interface{}, format string
 
 	// We wrap the error here rather than rely on on Panicf because there will
 	// be one more stack-frame than expected and there'd be no way for that
-	// method to know whether it should drop one frame or two.
+	// method to know whether it should drop one frame or two. For the same
+	// reason, we skip Panicf so that the caller is recorded correctly.
 
 	var err interface{}
 
@@ -467,26 +864,29 @@ func (l *Logger) PanicIff(ctx context.Context, errRaw interface{}, format string
 	if ok == true {
 		err = errRaw
 	} else {
-		err = errors.Wrap(errRaw, 1)
+		err = wrap(errRaw, 1)
 	}
 
-	l.Panicf(ctx, err.(error), format, args...)
+	l.panicf(ctx, err, format, args)
 }
 
 // Wrap returns a stack-wrapped error. If already stack-wrapped this is a no-op.
+// If an error further down its chain (see Is) is stack-wrapped, that stack is
+// used rather than starting a new one.
 func Wrap(err interface{}) *errors.Error {
 	es, ok := err.(*errors.Error)
 	if ok == true {
 		return es
 	}
 
-	return errors.Wrap(err, 1)
+	return wrap(err, 1)
 }
 
-// Errorf returns a stack-wrapped error with a string-substituted message.
+// Errorf returns a stack-wrapped error with a string-substituted message. Use
+// "%w" to wrap another error (like fmt.Errorf).
 func Errorf(message string, args ...interface{}) *errors.Error {
 	err := fmt.Errorf(message, args...)
-	return errors.Wrap(err, 1)
+	return wrap(err, 1)
 }
 
 // Panic panics with the error. Wrap if not already stack-wrapped.
@@ -495,7 +895,7 @@ func Panic(err interface{}) {
 	if ok == true {
 		panic(err)
 	} else {
-		panic(errors.Wrap(err, 1))
+		panic(wrap(err, 1))
 	}
 }
 
@@ -515,27 +915,45 @@ func PanicIf(err interface{}) {
 	if ok == true {
 		panic(err)
 	} else {
-		panic(errors.Wrap(err, 1))
+		panic(wrap(err, 1))
 	}
 }
 
-// Is checks if the left ("actual") error equals the right ("against") error.
-// The right must be an unwrapped error (the kind that you'd initialize as a
-// global variable). The left can be a wrapped or unwrapped error.
+// Is checks if the left ("actual") error equals the right ("against") error
+// or wraps it anywhere in its chain. Chains are followed through Unwrap()
+// (returning one error or several) as well as through stack-wrapped errors,
+// and errors in the chain with an Is(error) bool method are consulted. The
+// right may also be stack-wrapped.
 func Is(actual, against error) bool {
-	// If it's an unwrapped error.
-	if _, ok := actual.(*errors.Error); ok == false {
+	if actual == nil || against == nil {
 		return actual == against
 	}
 
-	return errors.Is(actual, against)
+	targets := []error{against}
+	if es, ok := against.(*errors.Error); ok == true && es.Err != nil {
+		targets = append(targets, es.Err)
+	}
+
+	return walkError(actual, func(err error) bool {
+		for _, target := range targets {
+			if reflect.TypeOf(err).Comparable() == true && err == target {
+				return true
+			}
+
+			if x, ok := err.(interface{ Is(error) bool }); ok == true && x.Is(target) == true {
+				return true
+			}
+		}
+
+		return false
+	})
 }
 
 // PrintError is a utility function to prevent the caller from having to import
 // the third-party library.
 func PrintError(err error) {
 	wrapped := Wrap(err)
-	fmt.Printf("Stack:\n\n%s\n", wrapped.ErrorStack())
+	fmt.Printf("Stack:\n\n%s\n", errorStack(wrapped))
 }
 
 // PrintErrorf is a utility function to prevent the caller from having to
@@ -545,36 +963,20 @@ func PrintErrorf(err error, format string, args ...interface{}) {
 
 	fmt.Printf(format, args...)
 	fmt.Printf("\n")
-	fmt.Printf("Stack:\n\n%s\n", wrapped.ErrorStack())
+	fmt.Printf("Stack:\n\n%s\n", errorStack(wrapped))
 }
 
 func init() {
-	if format == "" {
-		format = defaultFormat
-	}
-
-	if levelName == "" {
-		levelName = defaultLevelName
-	}
-
-	if includeNouns != "" {
-		for _, noun := range strings.Split(includeNouns, ",") {
-			AddIncludeFilter(noun)
+	updateRegistry(func(r *registry) {
+		if r.format == "" {
+			r.format = defaultFormat
 		}
-	}
 
-	if excludeNouns != "" {
-		for _, noun := range strings.Split(excludeNouns, ",") {
-			AddExcludeFilter(noun)
+		if r.levelName == "" {
+			r.levelName = defaultLevelName
 		}
-	}
 
-	if excludeBypassLevelName != "" {
-		excludeBypassLevelName = LogLevelName(strings.ToLower(string(excludeBypassLevelName)))
-
-		var found bool
-		if excludeBypassLevel, found = levelNameMap[excludeBypassLevelName]; found == false {
-			panic(e.New("exclude bypass-level is invalid"))
-		}
-	}
+		r.configIncludeFilters = parseNounFilters(r.includeNouns)
+		r.configExcludeFilters = parseNounFilters(r.excludeNouns)
+	})
 }
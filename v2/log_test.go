@@ -2,6 +2,8 @@ package log
 
 import (
 	e "errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"math/rand"
@@ -55,6 +57,9 @@ type testLogAdapter struct {
 	infoTriggered    bool
 	warningTriggered bool
 	errorTriggered   bool
+
+	lastContext *LogContext
+	lastMessage string
 }
 
 func newTestLogAdapter() LogAdapter {
@@ -65,24 +70,32 @@ func newTestLogAdapter() LogAdapter {
 
 func (tla *testLogAdapter) Debugf(lc *LogContext, message *string) error {
 	tla.debugTriggered = true
+	tla.lastContext = lc
+	tla.lastMessage = *message
 
 	return nil
 }
 
 func (tla *testLogAdapter) Infof(lc *LogContext, message *string) error {
 	tla.infoTriggered = true
+	tla.lastContext = lc
+	tla.lastMessage = *message
 
 	return nil
 }
 
 func (tla *testLogAdapter) Warningf(lc *LogContext, message *string) error {
 	tla.warningTriggered = true
+	tla.lastContext = lc
+	tla.lastMessage = *message
 
 	return nil
 }
 
 func (tla *testLogAdapter) Errorf(lc *LogContext, message *string) error {
 	tla.errorTriggered = true
+	tla.lastContext = lc
+	tla.lastMessage = *message
 
 	return nil
 }
@@ -90,18 +103,20 @@ func (tla *testLogAdapter) Errorf(lc *LogContext, message *string) error {
 // Tests
 
 func TestConfigurationOverride(t *testing.T) {
-	cs := getConfigState()
+	cs := Snapshot()
 	defer func() {
-		setConfigState(cs)
+		Restore(cs)
 	}()
 
-	levelName = "xyz"
+	updateRegistry(func(r *registry) {
+		r.levelName = "xyz"
+	})
 
 	// Overwrite configuration, first thing.
 	tcp := newTestConfigurationProvider(levelNameDebug)
 	LoadConfiguration(tcp)
 
-	if levelName != levelNameDebug {
+	if levelName := loadRegistry().levelName; levelName != levelNameDebug {
 		t.Fatalf("The test configuration-provider didn't override the level properly: [%s]", levelName)
 	}
 }
@@ -127,7 +142,9 @@ func TestConfigurationLevelDirectOverride(t *testing.T) {
 	}
 
 	// Set the level high to prevent logging, first.
-	levelName = levelNameError
+	updateRegistry(func(r *registry) {
+		r.levelName = levelNameError
+	})
 
 	// Force a reconfig (which will bring in the new level).
 	l.doConfigure(true)
@@ -142,7 +159,9 @@ func TestConfigurationLevelDirectOverride(t *testing.T) {
 	}
 
 	// Now, set the level low to allow logging.
-	levelName = levelNameDebug
+	updateRegistry(func(r *registry) {
+		r.levelName = levelNameDebug
+	})
 
 	// Force a reconfig (which will bring in the new level).
 	l.doConfigure(true)
@@ -158,9 +177,9 @@ func TestConfigurationLevelDirectOverride(t *testing.T) {
 }
 
 func TestConfigurationLevelProviderOverride(t *testing.T) {
-	cs := getConfigState()
+	cs := Snapshot()
 	defer func() {
-		setConfigState(cs)
+		Restore(cs)
 	}()
 
 	// Overwrite configuration, first thing.
@@ -241,9 +260,9 @@ func TestDefaultAdapterAssignment(t *testing.T) {
 }
 
 func TestAdapter(t *testing.T) {
-	cs := getConfigState()
+	cs := Snapshot()
 	defer func() {
-		setConfigState(cs)
+		Restore(cs)
 	}()
 
 	// Overwrite configuration, first thing.
@@ -286,9 +305,9 @@ func TestAdapter(t *testing.T) {
 func TestStaticConfiguration(t *testing.T) {
 	scp := NewStaticConfigurationProvider()
 
-	cs := getConfigState()
+	cs := Snapshot()
 	defer func() {
-		setConfigState(cs)
+		Restore(cs)
 	}()
 
 	scp.SetFormat("aa")
@@ -297,32 +316,39 @@ func TestStaticConfiguration(t *testing.T) {
 	scp.SetIncludeNouns("dd")
 	scp.SetExcludeNouns("ee")
 	scp.SetExcludeBypassLevelName("ff")
+	scp.SetNounLevels("gg")
 
 	LoadConfiguration(scp)
 
-	if format != "aa" {
+	r := loadRegistry()
+
+	if r.format != "aa" {
 		t.Fatalf("Static configuration provider was not set correctly: format")
 	}
 
-	if defaultAdapterName != "bb" {
+	if r.defaultAdapterName != "bb" {
 		t.Fatalf("Static configuration provider was not set correctly: defaultAdapterName")
 	}
 
-	if levelName != "cc" {
+	if r.levelName != "cc" {
 		t.Fatalf("Static configuration provider was not set correctly: levelName")
 	}
 
-	if includeNouns != "dd" {
+	if r.includeNouns != "dd" {
 		t.Fatalf("Static configuration provider was not set correctly: includeNouns")
 	}
 
-	if excludeNouns != "ee" {
+	if r.excludeNouns != "ee" {
 		t.Fatalf("Static configuration provider was not set correctly: excludeNouns")
 	}
 
-	if excludeBypassLevelName != "ff" {
+	if r.excludeBypassLevelName != "ff" {
 		t.Fatalf("Static configuration provider was not set correctly: excludeBypassLevelName")
 	}
+
+	if r.nounLevels != "gg" {
+		t.Fatalf("Static configuration provider was not set correctly: nounLevels")
+	}
 }
 
 func TestNoAdapter(t *testing.T) {
@@ -368,7 +394,9 @@ func TestNewLoggerWithAdapterName(t *testing.T) {
 
 	defer func() {
 		SetDefaultAdapterName(originalDefaultAdapterName)
-		delete(adapters, adapterName)
+		updateRegistry(func(r *registry) {
+			delete(r.adapters, adapterName)
+		})
 	}()
 
 	l := NewLoggerWithAdapterName(noun, adapterName)
@@ -411,3 +439,367 @@ func TestIs__wrapped__miss(t *testing.T) {
 		t.Fatalf("Is() should be false for a wrapped failure")
 	}
 }
+
+func TestLogger_With(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	child := l.With(NewField("request_id", "abc"), NewField("user", "joe"))
+
+	if child.Noun() != l.Noun() {
+		t.Fatalf("Child noun not correct: [%s]", child.Noun())
+	} else if len(l.fields) != 0 {
+		t.Fatalf("Parent logger should not have been modified.")
+	}
+
+	grandchild := child.With(NewField("user", "bob"))
+
+	grandchild.Infof(nil, "Info message")
+
+	expectedFields := Fields{
+		NewField("request_id", "abc"),
+		NewField("user", "bob"),
+	}
+
+	fields := tla.lastContext.Fields()
+	if reflect.DeepEqual(fields, expectedFields) != true {
+		t.Fatalf("Fields not correct: %v", fields)
+	}
+
+	if tla.lastMessage != "logTest: [INFO]  Info message request_id=abc user=bob" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+}
+
+func TestLogger_DebugFieldsf(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("logTest", "test").With(NewField("tenant", 11))
+
+	l.DebugFieldsf(nil, Fields{NewField("count", 22)}, "Debug message: %d", 33)
+
+	if tla.debugTriggered != true {
+		t.Fatalf("Debug message not getting through.")
+	}
+
+	expectedFields := Fields{
+		NewField("tenant", 11),
+		NewField("count", 22),
+	}
+
+	fields := tla.lastContext.Fields()
+	if reflect.DeepEqual(fields, expectedFields) != true {
+		t.Fatalf("Fields not correct: %v", fields)
+	}
+
+	if tla.lastMessage != "logTest: [DEBUG]  Debug message: 33 tenant=11 count=22" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+}
+
+func TestLogger_ErrorFieldsf(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	err := e.New("an error happened")
+	l.ErrorFieldsf(nil, err, Fields{NewField("path", "/a/b")}, "Error message")
+
+	if tla.errorTriggered != true {
+		t.Fatalf("Error message not getting through.")
+	}
+
+	value, found := tla.lastContext.Fields().Get("path")
+	if found != true {
+		t.Fatalf("Field not found.")
+	} else if value != "/a/b" {
+		t.Fatalf("Field value not correct: [%v]", value)
+	}
+}
+
+type countingStringer struct {
+	calls int
+}
+
+func (cs *countingStringer) String() string {
+	cs.calls++
+	return "value"
+}
+
+func TestLogger_Errorf__FormatsOnce(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("logTest", "test")
+
+	stringer := new(countingStringer)
+	l.Errorf(nil, e.New("an error happened"), "Error message: %s", stringer)
+
+	if stringer.calls != 1 {
+		t.Fatalf("Message should have been formatted once: (%d)", stringer.calls)
+	} else if tla.lastContext.Message() != "Error message: value" {
+		t.Fatalf("Message not correct: [%s]", tla.lastContext.Message())
+	} else if strings.HasPrefix(tla.lastMessage, "logTest: [ERROR]  Error message: value\n*errors.errorString an error happened\n") != true {
+		t.Fatalf("Stack not appended to the message: [%s]", tla.lastMessage)
+	}
+}
+
+func TestLogger_allowMessage__Patterns(t *testing.T) {
+	AddIncludeFilter("storage.**")
+	AddExcludeFilter("storage.cache")
+	AddExcludeFilter("*.verbose")
+
+	defer func() {
+		RemoveIncludeFilter("storage.**")
+		RemoveExcludeFilter("storage.cache")
+		RemoveExcludeFilter("*.verbose")
+	}()
+
+	cases := map[string]bool{
+		"storage":         true,
+		"storage.disk":    true,
+		"storage.cache":   false,
+		"storage.verbose": false,
+		"network":         false,
+	}
+
+	l := NewLogger("logTest")
+
+	for noun, expected := range cases {
+		if l.allowMessage(noun, LevelInfo) != expected {
+			t.Fatalf("Noun [%s] should have been allowed (%v).", noun, expected)
+		}
+	}
+}
+
+func TestLogger_allowMessage__ExcludePatternsOnly(t *testing.T) {
+	AddExcludeFilter("net.http.**")
+	AddExcludeFilter("*.cache")
+
+	defer func() {
+		RemoveExcludeFilter("net.http.**")
+		RemoveExcludeFilter("*.cache")
+	}()
+
+	cases := map[string]bool{
+		"net":             true,
+		"net.http":        false,
+		"net.http.client": false,
+		"storage.cache":   false,
+		"storage.disk":    true,
+	}
+
+	l := NewLogger("logTest")
+
+	for noun, expected := range cases {
+		if l.allowMessage(noun, LevelInfo) != expected {
+			t.Fatalf("Noun [%s] should have been allowed (%v).", noun, expected)
+		}
+	}
+}
+
+func TestLogger__Reconfiguration(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameError)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	gla1 := newGatedLogAdapter(false)
+	AddAdapter("test1", gla1)
+
+	l := NewLogger("logTest")
+
+	l.Infof(nil, "Filtered message")
+
+	if len(gla1.Messages()) != 0 {
+		t.Fatalf("Info message should have been filtered.")
+	}
+
+	// Change the level, format, and default adapter after the logger has
+	// already been used.
+
+	gla2 := newGatedLogAdapter(false)
+	AddAdapter("test2", gla2)
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameInfo)
+	scp.SetFormat("{{.Level}}: {{.Message}}")
+	scp.SetDefaultAdapterName("test2")
+
+	LoadConfiguration(scp)
+
+	l.Infof(nil, "Visible message")
+
+	if len(gla1.Messages()) != 0 {
+		t.Fatalf("Old default adapter should not have received anything.")
+	}
+
+	messages := gla2.Messages()
+	if len(messages) != 1 || messages[0] != "INFO: Visible message" {
+		t.Fatalf("Reconfiguration not picked up: %v", messages)
+	}
+}
+
+func TestLogger__Reconfiguration__Nouns(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("other", "test")
+
+	l.Infof(nil, "Visible message")
+
+	if tla.infoTriggered != true {
+		t.Fatalf("Message should not have been filtered before the reload.")
+	}
+
+	AddIncludeFilter("added")
+
+	// Reload with include-nouns after the logger has already been used.
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameDebug)
+	scp.SetIncludeNouns("only.this, storage.**")
+
+	LoadConfiguration(scp)
+
+	tla.infoTriggered = false
+	l.Infof(nil, "Filtered message")
+
+	if tla.infoTriggered != false {
+		t.Fatalf("Include-nouns not applied on reload.")
+	}
+
+	tla.infoTriggered = false
+	NewLoggerWithAdapterName("storage.disk", "test").Infof(nil, "Visible message")
+
+	if tla.infoTriggered != true {
+		t.Fatalf("Include-noun pattern not applied on reload.")
+	}
+
+	tla.infoTriggered = false
+	NewLoggerWithAdapterName("added", "test").Infof(nil, "Visible message")
+
+	if tla.infoTriggered != true {
+		t.Fatalf("Programmatic include filter dropped by the reload.")
+	}
+
+	// Reload again with exclude-nouns instead. The old include-nouns should
+	// be gone.
+
+	scp = NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameDebug)
+	scp.SetExcludeNouns("storage.*")
+
+	LoadConfiguration(scp)
+	RemoveIncludeFilter("added")
+
+	tla.infoTriggered = false
+	l.Infof(nil, "Visible message")
+
+	if tla.infoTriggered != true {
+		t.Fatalf("Previous include-nouns not replaced on reload.")
+	}
+
+	tla.infoTriggered = false
+	NewLoggerWithAdapterName("storage.disk", "test").Infof(nil, "Filtered message")
+
+	if tla.infoTriggered != false {
+		t.Fatalf("Exclude-nouns not applied on reload.")
+	}
+}
+
+func TestLogger_doConfigure__TemplateCached(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+	AddAdapter("test", newTestLogAdapter())
+
+	l1 := NewLoggerWithAdapterName("logTest1", "test")
+	ls1 := l1.doConfigure(false)
+
+	l2 := NewLoggerWithAdapterName("logTest2", "test")
+	ls2 := l2.doConfigure(false)
+
+	if ls1.t != ls2.t {
+		t.Fatalf("Template should have been shared between loggers.")
+	}
+
+	// Nothing changed, so this should be the same state.
+	if l1.doConfigure(false) != ls1 {
+		t.Fatalf("Logger should not have been reconfigured.")
+	}
+
+	LoadConfiguration(tcp)
+
+	ls3 := l1.doConfigure(false)
+	if ls3 == ls1 {
+		t.Fatalf("Logger should have been reconfigured.")
+	} else if ls3.t != ls1.t {
+		t.Fatalf("Template should not have been parsed again for the same format.")
+	}
+}
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// LogfmtLogAdapter writes one logfmt ("key=value") line per message to an
+// io.Writer. The line has the level, noun, message, bypass flag, and (when
+// caller capture is enabled) caller, followed by the error and the fields.
+// Values containing spaces, quotes, equal-signs, or control characters (e.g.
+// multi-line error stacks) are quoted and escaped.
+type LogfmtLogAdapter struct {
+	w io.Writer
+
+	m sync.Mutex
+}
+
+// NewLogfmtLogAdapter returns a new LogfmtLogAdapter.
+func NewLogfmtLogAdapter(w io.Writer) *LogfmtLogAdapter {
+	return &LogfmtLogAdapter{
+		w: w,
+	}
+}
+
+// Debugf logs a debugging message.
+func (lla *LogfmtLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return lla.write(lc)
+}
+
+// Infof logs an info message.
+func (lla *LogfmtLogAdapter) Infof(lc *LogContext, message *string) error {
+	return lla.write(lc)
+}
+
+// Warningf logs a warning message.
+func (lla *LogfmtLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return lla.write(lc)
+}
+
+// Errorf logs an error message.
+func (lla *LogfmtLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return lla.write(lc)
+}
+
+func (lla *LogfmtLogAdapter) write(lc *LogContext) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	b := new(bytes.Buffer)
+
+	writeLogfmtPair(b, "level", string(lc.LevelName()))
+	writeLogfmtPair(b, "noun", lc.Noun())
+	writeLogfmtPair(b, "msg", lc.Message())
+	writeLogfmtPair(b, "bypass", lc.ExcludeBypass())
+
+	if caller := lc.Caller(); caller.File != "" {
+		writeLogfmtPair(b, "caller", caller.String())
+	}
+
+	if lcErr := lc.Error(); lcErr != nil {
+		writeLogfmtPair(b, "error", lcErr.Error())
+		writeLogfmtPair(b, "stack", lc.ErrorStack())
+	}
+
+	for _, f := range lc.Fields() {
+		writeLogfmtPair(b, f.Name, f.Value)
+	}
+
+	b.WriteString("\n")
+
+	lla.m.Lock()
+	defer lla.m.Unlock()
+
+	_, err = lla.w.Write(b.Bytes())
+	PanicIf(err)
+
+	return nil
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key string, value interface{}) {
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+
+	b.WriteString(logfmtKey(key))
+	b.WriteString("=")
+	b.WriteString(logfmtValue(value))
+}
+
+// logfmtKey replaces any characters that would make the key ambiguous.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) == true || unicode.IsPrint(r) == false {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+// logfmtValue renders the value and quotes it if necessary.
+func logfmtValue(value interface{}) string {
+	var s string
+
+	switch v := value.(type) {
+	case nil:
+		s = ""
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	default:
+		s = fmt.Sprintf("%v", v)
+	}
+
+	if logfmtNeedsQuoting(s) == true {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+func logfmtNeedsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if r == '=' || r == '"' || r == '\\' || unicode.IsSpace(r) == true || unicode.IsPrint(r) == false {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLogfmtLogAdapter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	lla := NewLogfmtLogAdapter(b)
+	AddAdapter("logfmt", lla)
+
+	l := NewLoggerWithAdapterName("logfmtTest", "logfmt").With(NewField("user", "joe"))
+	l.WarningFieldsf(nil, Fields{NewField("path", "/a b"), NewField("count", 5)}, "Warning %s", "message")
+
+	expected := `level=warning noun=logfmtTest msg="Warning message" bypass=false user=joe path="/a b" count=5` + "\n"
+	if b.String() != expected {
+		t.Fatalf("Line not correct: [%s]", b.String())
+	}
+}
+
+func TestLogfmtLogAdapter__Caller(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	lla := NewLogfmtLogAdapter(b)
+	AddAdapter("logfmt", lla)
+
+	l := NewLoggerWithAdapterName("logfmtTest", "logfmt")
+
+	SetCallerCapture(false)
+	l.Infof(nil, "Info message")
+
+	if strings.Contains(b.String(), "caller=") == true {
+		t.Fatalf("Caller should be omitted when not captured: [%s]", b.String())
+	}
+
+	b.Reset()
+
+	SetCallerCapture(true)
+	l.Infof(nil, "Info message")
+
+	expected := fmt.Sprintf("level=info noun=logfmtTest msg=\"Info message\" bypass=false caller=logfmt_adapter_test.go:%d\n", currentLine()-2)
+	if b.String() != expected {
+		t.Fatalf("Caller not emitted: [%s]", b.String())
+	}
+}
+
+func TestLogfmtLogAdapter__Error(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	lla := NewLogfmtLogAdapter(b)
+	AddAdapter("logfmt", lla)
+
+	l := NewLoggerWithAdapterName("logfmtTest", "logfmt")
+	l.Errorf(nil, e.New("an error happened"), "Error message")
+
+	line := b.String()
+
+	if strings.Count(line, "\n") != 1 || strings.HasSuffix(line, "\n") != true {
+		t.Fatalf("Multi-line stack was not escaped: [%s]", line)
+	} else if strings.HasPrefix(line, `level=error noun=logfmtTest msg="Error message" bypass=false error="an error happened" stack="`) != true {
+		t.Fatalf("Line not correct: [%s]", line)
+	} else if strings.Contains(line, `\n`) != true {
+		t.Fatalf("Newlines in the stack were not escaped: [%s]", line)
+	}
+}
+
+func TestLogfmtValue(t *testing.T) {
+	cases := map[interface{}]string{
+		"simple":         "simple",
+		"":               `""`,
+		"two words":      `"two words"`,
+		`say "hi"`:       `"say \"hi\""`,
+		"a=b":            `"a=b"`,
+		"line1\nline2":   `"line1\nline2"`,
+		`back\slash`:     `"back\\slash"`,
+		123:              "123",
+		true:             "true",
+		e.New("failed!"): "failed!",
+	}
+
+	for value, expected := range cases {
+		actual := logfmtValue(value)
+		if actual != expected {
+			t.Fatalf("Value [%v] not rendered correctly: [%s] != [%s]", value, actual, expected)
+		}
+	}
+}
+
+func TestLogfmtKey(t *testing.T) {
+	if logfmtKey("a b=c") != "a_b_c" {
+		t.Fatalf("Key not sanitized: [%s]", logfmtKey("a b=c"))
+	} else if logfmtKey("") != "_" {
+		t.Fatalf("Empty key not sanitized.")
+	}
+}
@@ -0,0 +1,179 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MultiLogAdapterError is passed to the error-handler of a MultiLogAdapter
+// when one or more of its children failed. Every child is still given the
+// message.
+type MultiLogAdapterError struct {
+	// Errors are the failures keyed by adapter name.
+	Errors map[string]error
+}
+
+// Error returns a description of every failure.
+func (mlae *MultiLogAdapterError) Error() string {
+	adapterNames := make([]string, 0, len(mlae.Errors))
+	for adapterName := range mlae.Errors {
+		adapterNames = append(adapterNames, adapterName)
+	}
+
+	sort.Strings(adapterNames)
+
+	parts := make([]string, len(adapterNames))
+	for i, adapterName := range adapterNames {
+		parts[i] = fmt.Sprintf("[%s]: %s", adapterName, mlae.Errors[adapterName].Error())
+	}
+
+	return fmt.Sprintf("one or more adapters failed: %s", strings.Join(parts, ", "))
+}
+
+type multiLogAdapterChild struct {
+	adapterName  string
+	minimumLevel LogLevel
+}
+
+// MultiLogAdapter forwards each message to several registered adapters. Each
+// child has its own minimum level, which is applied after the logger's own
+// level. A failing child doesn't fail the message, since that would panic in
+// the logging goroutine even though the other children got it. Instead, the
+// failures are passed to the error-handler (which prints them to STDERR by
+// default).
+type MultiLogAdapter struct {
+	children     []multiLogAdapterChild
+	errorHandler func(err error)
+	m            sync.RWMutex
+}
+
+// NewMultiLogAdapter returns a new MultiLogAdapter with no children.
+func NewMultiLogAdapter() *MultiLogAdapter {
+	return &MultiLogAdapter{
+		errorHandler: printMultiError,
+	}
+}
+
+// SetErrorHandler sets the function that receives a *MultiLogAdapterError
+// when one or more children fail.
+func (mla *MultiLogAdapter) SetErrorHandler(errorHandler func(err error)) {
+	mla.m.Lock()
+	defer mla.m.Unlock()
+
+	mla.errorHandler = errorHandler
+}
+
+// AddChild forwards messages at or above the given level to the adapter
+// registered with the given name. The adapter is looked-up when logging, so
+// it does not have to be registered yet.
+func (mla *MultiLogAdapter) AddChild(adapterName string, minimumLevel LogLevel) {
+	mla.m.Lock()
+	defer mla.m.Unlock()
+
+	child := multiLogAdapterChild{
+		adapterName:  adapterName,
+		minimumLevel: minimumLevel,
+	}
+
+	mla.children = append(mla.children, child)
+}
+
+// Tracef forwards a trace message.
+func (mla *MultiLogAdapter) Tracef(lc *LogContext, message *string) error {
+	return mla.dispatch(lc, LevelTrace, message)
+}
+
+// Debugf forwards a debugging message.
+func (mla *MultiLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return mla.dispatch(lc, LevelDebug, message)
+}
+
+// Infof forwards an info message.
+func (mla *MultiLogAdapter) Infof(lc *LogContext, message *string) error {
+	return mla.dispatch(lc, LevelInfo, message)
+}
+
+// Warningf forwards a warning message.
+func (mla *MultiLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return mla.dispatch(lc, LevelWarning, message)
+}
+
+// Errorf forwards an error message.
+func (mla *MultiLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return mla.dispatch(lc, LevelError, message)
+}
+
+// Criticalf forwards a critical message.
+func (mla *MultiLogAdapter) Criticalf(lc *LogContext, message *string) error {
+	return mla.dispatch(lc, LevelCritical, message)
+}
+
+func (mla *MultiLogAdapter) dispatch(lc *LogContext, methodLevel LogLevel, message *string) error {
+	level := messageLevel(lc, methodLevel)
+
+	mla.m.RLock()
+	children := mla.children
+	errorHandler := mla.errorHandler
+	mla.m.RUnlock()
+
+	var errs map[string]error
+
+	for _, child := range children {
+		if level < child.minimumLevel {
+			continue
+		}
+
+		if err := mla.dispatchOne(child.adapterName, lc, level, message); err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+
+			errs[child.adapterName] = err
+		}
+	}
+
+	if errs != nil {
+		if errorHandler == nil {
+			errorHandler = printMultiError
+		}
+
+		errorHandler(&MultiLogAdapterError{
+			Errors: errs,
+		})
+	}
+
+	return nil
+}
+
+// dispatchOne forwards to a single child, converting a panic into an error so
+// that the remaining children still get the message.
+func (mla *MultiLogAdapter) dispatchOne(adapterName string, lc *LogContext, level LogLevel, message *string) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state)
+		}
+	}()
+
+	la, found := findAdapter(loadRegistry(), adapterName)
+	if found == false {
+		Panicf("adapter is not valid: %s", adapterName)
+	}
+
+	if la == LogAdapter(mla) {
+		Panicf("adapter can not forward to itself: %s", adapterName)
+	}
+
+	lm := adapterMethod(la, level)
+
+	err = lm(lc, message)
+	PanicIf(err)
+
+	return nil
+}
+
+func printMultiError(err error) {
+	fmt.Fprintf(os.Stderr, "multi log-adapter could not write message: %s\n", err)
+}
@@ -0,0 +1,133 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestMultiLogAdapter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	mla := NewMultiLogAdapter()
+	mla.AddChild("console", LevelDebug)
+	mla.AddChild("file", LevelWarning)
+
+	AddAdapter("multi", mla)
+
+	consoleAdapter := newGatedLogAdapter(false)
+	AddAdapter("console", consoleAdapter)
+
+	fileAdapter := newGatedLogAdapter(false)
+	AddAdapter("file", fileAdapter)
+
+	l := NewLoggerWithAdapterName("multiTest", "multi")
+
+	l.Debugf(nil, "Debug message")
+	l.Warningf(nil, "Warning message")
+
+	consoleMessages := consoleAdapter.Messages()
+	if len(consoleMessages) != 2 {
+		t.Fatalf("Console adapter should have received both messages: %v", consoleMessages)
+	}
+
+	fileMessages := fileAdapter.Messages()
+	if len(fileMessages) != 1 || fileMessages[0] != "multiTest: [WARNING]  Warning message" {
+		t.Fatalf("File adapter should have received only the warning: %v", fileMessages)
+	}
+}
+
+func TestMultiLogAdapter__ErrorIsolation(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	mla := NewMultiLogAdapter()
+	mla.AddChild("failing", LevelDebug)
+	mla.AddChild("missing", LevelDebug)
+	mla.AddChild("working", LevelDebug)
+
+	AddAdapter("failing", &failingLogAdapter{})
+
+	workingAdapter := newGatedLogAdapter(false)
+	AddAdapter("working", workingAdapter)
+
+	var handled []error
+	mla.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	message := "message"
+	if err := mla.Infof(&LogContext{}, &message); err != nil {
+		t.Fatalf("Child failures should not be returned: [%v]", err)
+	} else if len(handled) != 1 {
+		t.Fatalf("Expected one call to the error-handler: %v", handled)
+	}
+
+	mlae, ok := handled[0].(*MultiLogAdapterError)
+	if ok != true {
+		t.Fatalf("Expected a MultiLogAdapterError: [%v]", handled[0])
+	} else if len(mlae.Errors) != 2 {
+		t.Fatalf("Expected two failures: %v", mlae.Errors)
+	} else if Is(mlae.Errors["failing"], errTestAdapterFailure) != true {
+		t.Fatalf("Failing adapter's error not correct: [%v]", mlae.Errors["failing"])
+	} else if mlae.Errors["missing"] == nil {
+		t.Fatalf("Missing adapter should have failed.")
+	}
+
+	workingMessages := workingAdapter.Messages()
+	if len(workingMessages) != 1 || workingMessages[0] != "message" {
+		t.Fatalf("Working adapter did not receive the message: %v", workingMessages)
+	}
+}
+
+func TestMultiLogAdapter__ErrorIsolation__ThroughLogger(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	mla := NewMultiLogAdapter()
+	mla.AddChild("failing", LevelDebug)
+	mla.AddChild("working", LevelDebug)
+
+	var handled []error
+	mla.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	AddAdapter("multi", mla)
+	AddAdapter("failing", &failingLogAdapter{})
+
+	workingAdapter := newGatedLogAdapter(false)
+	AddAdapter("working", workingAdapter)
+
+	l := NewLoggerWithAdapterName("multiTest", "multi")
+
+	// This would panic if the failure were returned to the logger.
+	l.Infof(nil, "Info message")
+
+	workingMessages := workingAdapter.Messages()
+	if len(workingMessages) != 1 {
+		t.Fatalf("Working adapter did not receive the message: %v", workingMessages)
+	} else if len(handled) != 1 {
+		t.Fatalf("Failure not passed to the error-handler: %v", handled)
+	}
+}
@@ -0,0 +1,156 @@
+package log
+
+import (
+	"strings"
+)
+
+const (
+	nounSeparator = "."
+
+	// nounWildcardSegment matches exactly one segment of a noun.
+	nounWildcardSegment = "*"
+
+	// nounWildcardDeep matches zero or more segments of a noun.
+	nounWildcardDeep = "**"
+)
+
+// nounSpecificity describes how specific a noun-pattern is so that the most
+// specific of several matching filters can win.
+type nounSpecificity struct {
+	literalSegments int
+	deepWildcards   int
+	wildcards       int
+}
+
+// isMoreSpecificThan returns true if this specificity outranks the other. A
+// pattern is more specific if it has more literal segments or, with an equal
+// number, fewer "**" segments or, failing that, fewer "*" segments. An exact
+// noun is therefore always the most specific match.
+func (ns nounSpecificity) isMoreSpecificThan(other nounSpecificity) bool {
+	if ns.literalSegments != other.literalSegments {
+		return ns.literalSegments > other.literalSegments
+	} else if ns.deepWildcards != other.deepWildcards {
+		return ns.deepWildcards < other.deepWildcards
+	}
+
+	return ns.wildcards < other.wildcards
+}
+
+// nounPattern is a parsed noun filter (e.g. "storage.*", "*.cache", or
+// "net.http.**").
+type nounPattern struct {
+	segments    []string
+	specificity nounSpecificity
+}
+
+func newNounPattern(pattern string) nounPattern {
+	segments := strings.Split(pattern, nounSeparator)
+
+	ns := nounSpecificity{}
+	for _, segment := range segments {
+		switch segment {
+		case nounWildcardDeep:
+			ns.deepWildcards++
+		case nounWildcardSegment:
+			ns.wildcards++
+		default:
+			ns.literalSegments++
+		}
+	}
+
+	return nounPattern{
+		segments:    segments,
+		specificity: ns,
+	}
+}
+
+// matches returns true if the noun is matched by the pattern.
+func (np nounPattern) matches(noun string) bool {
+	return matchNounSegments(np.segments, strings.Split(noun, nounSeparator))
+}
+
+func matchNounSegments(patternSegments, nounSegments []string) bool {
+	for len(patternSegments) > 0 {
+		current := patternSegments[0]
+
+		if current == nounWildcardDeep {
+			// Try to match the rest of the pattern at every remaining
+			// position (including consuming nothing).
+			for i := 0; i <= len(nounSegments); i++ {
+				if matchNounSegments(patternSegments[1:], nounSegments[i:]) == true {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(nounSegments) == 0 {
+			return false
+		}
+
+		if current != nounWildcardSegment && current != nounSegments[0] {
+			return false
+		}
+
+		patternSegments = patternSegments[1:]
+		nounSegments = nounSegments[1:]
+	}
+
+	return len(nounSegments) == 0
+}
+
+// findNounMatch returns the specificity of the most specific pattern that
+// matches the noun.
+func findNounMatch(patterns map[string]nounPattern, noun string) (best nounSpecificity, found bool) {
+	for _, np := range patterns {
+		if np.matches(noun) == false {
+			continue
+		}
+
+		if found == false || np.specificity.isMoreSpecificThan(best) == true {
+			best = np.specificity
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// findNounMatchIn is findNounMatch over several sets of patterns.
+func findNounMatchIn(noun string, patternSets ...map[string]nounPattern) (best nounSpecificity, found bool) {
+	for _, patterns := range patternSets {
+		specificity, setFound := findNounMatch(patterns, noun)
+		if setFound == false {
+			continue
+		}
+
+		if found == false || specificity.isMoreSpecificThan(best) == true {
+			best = specificity
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// parseNounFilters parses a configuration-driven comma-separated list of nouns
+// (or patterns) into filters.
+func parseNounFilters(nouns string) map[string]nounPattern {
+	filters := make(map[string]nounPattern)
+
+	if nouns == "" {
+		return filters
+	}
+
+	for _, noun := range strings.Split(nouns, ",") {
+		noun = strings.TrimSpace(noun)
+		if noun == "" {
+			continue
+		}
+
+		filters[noun] = newNounPattern(noun)
+	}
+
+	return filters
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestNounPattern_matches(t *testing.T) {
+	cases := []struct {
+		pattern string
+		noun    string
+		matches bool
+	}{
+		{"storage", "storage", true},
+		{"storage", "storage.disk", false},
+		{"storage.*", "storage.disk", true},
+		{"storage.*", "storage", false},
+		{"storage.*", "storage.disk.cache", false},
+		{"*.cache", "storage.cache", true},
+		{"*.cache", "cache", false},
+		{"*.cache", "storage.disk.cache", false},
+		{"net.http.**", "net.http", true},
+		{"net.http.**", "net.http.client", true},
+		{"net.http.**", "net.http.client.pool", true},
+		{"net.http.**", "net.https", false},
+		{"**.cache", "storage.disk.cache", true},
+		{"**.cache", "cache", true},
+		{"a.**.z", "a.b.c.z", true},
+		{"a.**.z", "a.z", true},
+		{"a.**.z", "a.b.c", false},
+		{"**", "anything.at.all", true},
+	}
+
+	for _, c := range cases {
+		np := newNounPattern(c.pattern)
+		if np.matches(c.noun) != c.matches {
+			t.Fatalf("Pattern [%s] against noun [%s] should have been (%v).", c.pattern, c.noun, c.matches)
+		}
+	}
+}
+
+func TestNounSpecificity_isMoreSpecificThan(t *testing.T) {
+	exact := newNounPattern("net.http.client").specificity
+	singleWildcard := newNounPattern("net.http.*").specificity
+	deepWildcard := newNounPattern("net.http.**").specificity
+	shallow := newNounPattern("net.**").specificity
+
+	if exact.isMoreSpecificThan(singleWildcard) != true {
+		t.Fatalf("Exact should be more specific than a wildcard.")
+	} else if singleWildcard.isMoreSpecificThan(deepWildcard) != true {
+		t.Fatalf("Single wildcard should be more specific than a deep wildcard.")
+	} else if deepWildcard.isMoreSpecificThan(shallow) != true {
+		t.Fatalf("Longer prefix should be more specific than a shorter one.")
+	} else if shallow.isMoreSpecificThan(shallow) != false {
+		t.Fatalf("A specificity should not be more specific than itself.")
+	}
+}
+
+func TestFindNounMatch(t *testing.T) {
+	patterns := map[string]nounPattern{
+		"net.**":      newNounPattern("net.**"),
+		"net.http.*":  newNounPattern("net.http.*"),
+		"storage.*":   newNounPattern("storage.*"),
+		"unrelated.x": newNounPattern("unrelated.x"),
+	}
+
+	best, found := findNounMatch(patterns, "net.http.client")
+	if found != true {
+		t.Fatalf("Expected a match.")
+	} else if best != patterns["net.http.*"].specificity {
+		t.Fatalf("Most specific match not returned: %v", best)
+	}
+
+	_, found = findNounMatch(patterns, "other")
+	if found != false {
+		t.Fatalf("Expected no match.")
+	}
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetNounLevel overrides the system level for the given noun and every noun
+// beneath it in the dotted hierarchy (e.g. "db" also applies to "db.pool"
+// unless "db.pool" has its own level).
+func SetNounLevel(noun string, level LogLevel) {
+	updateRegistry(func(r *registry) {
+		r.nounLevelOverrides[noun] = level
+	})
+}
+
+// RemoveNounLevel removes a level override set with SetNounLevel.
+func RemoveNounLevel(noun string) {
+	updateRegistry(func(r *registry) {
+		delete(r.nounLevelOverrides, noun)
+	})
+}
+
+// parseNounLevels parses a comma-separated list of "noun=level" pairs (e.g.
+// "db=debug,http=warning").
+func parseNounLevels(raw string, levelsByName map[LogLevelName]LogLevel) (nounLevels map[string]LogLevel, err error) {
+	nounLevels = make(map[string]LogLevel)
+
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("noun-level not valid: [%s]", pair)
+		}
+
+		noun := strings.TrimSpace(parts[0])
+		levelName := LogLevelName(strings.ToLower(strings.TrimSpace(parts[1])))
+
+		level, found := levelsByName[levelName]
+		if found == false {
+			return nil, fmt.Errorf("noun-level has an invalid level: [%s]", pair)
+		}
+
+		nounLevels[noun] = level
+	}
+
+	return nounLevels, nil
+}
+
+// findNounLevel returns the level for the most specific (longest) noun in the
+// hierarchy of the given noun that has one.
+func findNounLevel(noun string, configured, overrides map[string]LogLevel) (level LogLevel, found bool) {
+	current := noun
+	for {
+		if level, found := overrides[current]; found == true {
+			return level, true
+		}
+
+		if level, found := configured[current]; found == true {
+			return level, true
+		}
+
+		i := strings.LastIndex(current, nounSeparator)
+		if i == -1 {
+			return 0, false
+		}
+
+		current = current[:i]
+	}
+}
@@ -0,0 +1,177 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNounLevels(t *testing.T) {
+	nounLevels, err := parseNounLevels("db=debug, http=WARNING,,", levelNameMap)
+	PanicIf(err)
+
+	expected := map[string]LogLevel{
+		"db":   LevelDebug,
+		"http": LevelWarning,
+	}
+
+	if reflect.DeepEqual(nounLevels, expected) != true {
+		t.Fatalf("Noun-levels not parsed correctly: %v", nounLevels)
+	}
+}
+
+func TestParseNounLevels__Empty(t *testing.T) {
+	nounLevels, err := parseNounLevels("", levelNameMap)
+	PanicIf(err)
+
+	if len(nounLevels) != 0 {
+		t.Fatalf("Expected no noun-levels: %v", nounLevels)
+	}
+}
+
+func TestParseNounLevels__Invalid(t *testing.T) {
+	_, err := parseNounLevels("db", levelNameMap)
+	if err == nil {
+		t.Fatalf("Expected error for missing level.")
+	}
+
+	_, err = parseNounLevels("db=loud", levelNameMap)
+	if err == nil {
+		t.Fatalf("Expected error for invalid level.")
+	}
+}
+
+func TestFindNounLevel(t *testing.T) {
+	configured := map[string]LogLevel{
+		"db":      LevelDebug,
+		"db.pool": LevelWarning,
+	}
+
+	overrides := map[string]LogLevel{
+		"db.pool.conn": LevelError,
+		"db":           LevelInfo,
+	}
+
+	cases := map[string]LogLevel{
+		"db":                LevelInfo,
+		"db.query":          LevelInfo,
+		"db.pool":           LevelWarning,
+		"db.pool.idle":      LevelWarning,
+		"db.pool.conn":      LevelError,
+		"db.pool.conn.slow": LevelError,
+	}
+
+	for noun, expected := range cases {
+		level, found := findNounLevel(noun, configured, overrides)
+		if found != true {
+			t.Fatalf("Level not found for noun [%s].", noun)
+		} else if level != expected {
+			t.Fatalf("Level for noun [%s] not correct: (%d) != (%d)", noun, level, expected)
+		}
+	}
+
+	_, found := findNounLevel("dbx", configured, overrides)
+	if found != false {
+		t.Fatalf("Level should not be found for an unrelated noun.")
+	}
+}
+
+func TestNounLevels__Configured(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameError)
+	scp.SetNounLevels("db=debug,http=warning")
+
+	LoadConfiguration(scp)
+
+	ClearAdapters()
+
+	dbAdapter := newGatedLogAdapter(false)
+	AddAdapter("db", dbAdapter)
+
+	httpAdapter := newGatedLogAdapter(false)
+	AddAdapter("http", httpAdapter)
+
+	dbLog := NewLoggerWithAdapterName("db.pool", "db")
+	dbLog.Debugf(nil, "Debug message")
+
+	if len(dbAdapter.Messages()) != 1 {
+		t.Fatalf("Debug message should have been logged for a child of [db].")
+	}
+
+	httpLog := NewLoggerWithAdapterName("http", "http")
+	httpLog.Infof(nil, "Info message")
+	httpLog.Warningf(nil, "Warning message")
+
+	httpMessages := httpAdapter.Messages()
+	if len(httpMessages) != 1 || httpMessages[0] != "http: [WARNING]  Warning message" {
+		t.Fatalf("Only the warning should have been logged for [http]: %v", httpMessages)
+	}
+}
+
+func TestNounLevels__ProviderWithout(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	var _ NounLevelsConfigurationProvider = NewEnvironmentConfigurationProvider()
+	var _ NounLevelsConfigurationProvider = NewStaticConfigurationProvider()
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetNounLevels("db=debug")
+
+	LoadConfiguration(scp)
+
+	// testConfigurationProvider doesn't implement
+	// NounLevelsConfigurationProvider, so the noun levels are cleared.
+
+	tcp := newTestConfigurationProvider(levelNameError)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	dbAdapter := newGatedLogAdapter(false)
+	AddAdapter("db", dbAdapter)
+
+	dbLog := NewLoggerWithAdapterName("db", "db")
+	dbLog.Debugf(nil, "Debug message")
+
+	if loadRegistry().nounLevels != "" {
+		t.Fatalf("Noun levels should have been cleared: [%s]", loadRegistry().nounLevels)
+	} else if len(dbAdapter.Messages()) != 0 {
+		t.Fatalf("Debug message should not have been logged for [db].")
+	}
+}
+
+func TestSetNounLevel(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameError)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	gla := newGatedLogAdapter(false)
+	AddAdapter("test", gla)
+
+	SetNounLevel("storage", LevelInfo)
+	defer RemoveNounLevel("storage")
+
+	storageLog := NewLoggerWithAdapterName("storage.disk", "test")
+	storageLog.Infof(nil, "Info message")
+
+	otherLog := NewLoggerWithAdapterName("other", "test")
+	otherLog.Infof(nil, "Info message")
+
+	messages := gla.Messages()
+	if len(messages) != 1 || messages[0] != "storage.disk: [INFO]  Info message" {
+		t.Fatalf("Only the storage message should have been logged: %v", messages)
+	}
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// PanicValueError is what a recovered panic is converted to when the value
+// that was panicked with isn't an error.
+type PanicValueError struct {
+	Value interface{}
+}
+
+// Error returns the panic value as a message.
+func (pve *PanicValueError) Error() string {
+	return fmt.Sprintf("panic: %v", pve.Value)
+}
+
+// recoveredError converts a recovered value to a stack-wrapped error. It must
+// be called directly by the deferred function that recovered the value. Errors
+// that are already stack-wrapped (e.g. from PanicIf) keep their stack.
+// Otherwise, the stack is captured here, which, while panicking, still
+// includes the frames down to where the panic happened.
+func recoveredError(state interface{}) *errors.Error {
+	err, ok := state.(error)
+	if ok == false {
+		err = &PanicValueError{
+			Value: state,
+		}
+	}
+
+	// Skip this function, the deferred function, and the runtime's panic
+	// handler.
+	return wrap(err, 3)
+}
+
+// panicSitePC returns the PC of the first frame of the error's stack that
+// isn't in the runtime (the frames of a runtime panic start there) or zero if
+// the stack is empty.
+func panicSitePC(es *errors.Error) uintptr {
+	callers := es.Callers()
+	if len(callers) == 0 {
+		return 0
+	}
+
+	frames := runtime.CallersFrames(callers)
+	for {
+		frame, more := frames.Next()
+
+		if pkg, _ := splitFunctionName(frame.Function); pkg != "runtime" {
+			return frame.PC + 1
+		}
+
+		if more == false {
+			return 0
+		}
+	}
+}
+
+// Recover converts a panic into a stack-wrapped error and assigns it to the
+// error that errp points to. Panic values that aren't errors are converted to
+// a PanicValueError and runtime errors (e.g. nil dereferences) are kept in the
+// chain (see As). It must be deferred directly:
+//
+//	func DoSomething() (err error) {
+//	    defer log.Recover(&err)
+//
+//	    // ...
+//	}
+func Recover(errp *error) {
+	state := recover()
+	if state == nil {
+		return
+	}
+
+	*errp = recoveredError(state)
+}
+
+// RecoverAndLog logs a panic at the error level and swallows it. It must be
+// deferred directly.
+func (l *Logger) RecoverAndLog(ctx context.Context) {
+	state := recover()
+	if state == nil {
+		return
+	}
+
+	l.logRecovered(ctx, recoveredError(state))
+}
+
+// RecoverAndRepanic logs a panic at the error level and then panics again
+// with it as a stack-wrapped error. It must be deferred directly.
+func (l *Logger) RecoverAndRepanic(ctx context.Context) {
+	state := recover()
+	if state == nil {
+		return
+	}
+
+	es := recoveredError(state)
+	l.logRecovered(ctx, es)
+
+	panic(es)
+}
+
+// logRecovered logs a recovered error with the panic site as the caller.
+func (l *Logger) logRecovered(ctx context.Context, es *errors.Error) {
+	ls := l.doConfigure(false)
+
+	cs := callerSource{
+		skip: 2,
+		pc:   panicSitePC(es),
+	}
+
+	l.logFrom(ls, ctx, cs, LevelError, es, nil, "Recovered from a panic.", nil)
+}
@@ -0,0 +1,223 @@
+package log
+
+import (
+	e "errors"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+type recoverTestStruct struct {
+	value int
+}
+
+func recoverFromValue(value interface{}) (err error) {
+	defer Recover(&err)
+
+	panic(value)
+}
+
+func recoverFromNilDereference() (err error) {
+	defer Recover(&err)
+
+	var rts *recoverTestStruct
+	rts.value++
+
+	return nil
+}
+
+func recoverFromIndex(i int) (err error) {
+	defer Recover(&err)
+
+	values := []int{1, 2}
+	values[i] = 3
+
+	return nil
+}
+
+func recoverFromPanicIf() (err error) {
+	defer Recover(&err)
+
+	PanicIf(e.New("an error happened"))
+	return nil
+}
+
+// topFunction returns the function of the first frame of the error's stack.
+func topFunction(err error) string {
+	frames := err.(*errors.Error).StackFrames()
+	if len(frames) == 0 {
+		return ""
+	}
+
+	return frames[0].Name
+}
+
+func TestRecover__NoPanic(t *testing.T) {
+	original := e.New("an error happened")
+
+	err := func() (err error) {
+		defer Recover(&err)
+
+		return original
+	}()
+
+	if err != original {
+		t.Fatalf("Error should not be changed without a panic: [%v]", err)
+	}
+}
+
+func TestRecover__Error(t *testing.T) {
+	original := e.New("an error happened")
+	err := recoverFromValue(original)
+
+	if err == nil {
+		t.Fatalf("Panic not converted.")
+	} else if err.Error() != "an error happened" {
+		t.Fatalf("Message not correct: [%s]", err.Error())
+	} else if Is(err, original) != true {
+		t.Fatalf("Original error not found in the chain.")
+	} else if topFunction(err) != "recoverFromValue" {
+		t.Fatalf("Stack should start at the panic: [%s]\n%s", topFunction(err), err.(*errors.Error).ErrorStack())
+	}
+}
+
+func TestRecover__NonError(t *testing.T) {
+	err := recoverFromValue(11)
+
+	var pve *PanicValueError
+	if As(err, &pve) != true {
+		t.Fatalf("Panic value not converted: [%T]", err)
+	} else if pve.Value != 11 {
+		t.Fatalf("Panic value not correct: [%v]", pve.Value)
+	} else if err.Error() != "panic: 11" {
+		t.Fatalf("Message not correct: [%s]", err.Error())
+	} else if topFunction(err) != "recoverFromValue" {
+		t.Fatalf("Stack should start at the panic: [%s]", topFunction(err))
+	}
+}
+
+func TestRecover__RuntimeError(t *testing.T) {
+	cases := map[string]func() error{
+		"nil-dereference": recoverFromNilDereference,
+		"index":           func() error { return recoverFromIndex(5) },
+	}
+
+	for name, cb := range cases {
+		err := cb()
+
+		var re runtime.Error
+		if As(err, &re) != true {
+			t.Fatalf("Runtime error not found in [%s] chain: [%T]", name, err)
+		}
+
+		es := err.(*errors.Error)
+		if strings.Contains(es.ErrorStack(), "recoverFrom") != true {
+			t.Fatalf("Panic site not in [%s] stack:\n%s", name, es.ErrorStack())
+		} else if caller := getCallerFromPC(panicSitePC(es)); strings.HasPrefix(caller.Function, "recoverFrom") != true {
+			t.Fatalf("Panic site for [%s] not correct: %v", name, caller)
+		}
+	}
+}
+
+func TestRecover__StackWrapped(t *testing.T) {
+	err := recoverFromPanicIf()
+
+	if err.Error() != "an error happened" {
+		t.Fatalf("Message not correct: [%s]", err.Error())
+	} else if topFunction(err) != "recoverFromPanicIf" {
+		t.Fatalf("Stack from PanicIf not kept: [%s]", topFunction(err))
+	}
+}
+
+func TestLogger_RecoverAndLog(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+
+	var expectedLine int
+
+	func() {
+		defer l.RecoverAndLog(nil)
+
+		expectedLine = currentLine() + 1
+		panic("something broke")
+	}()
+
+	if tla.errorTriggered != true {
+		t.Fatalf("Panic not logged.")
+	} else if strings.HasPrefix(tla.lastMessage, "callerTest: [ERROR]  Recovered from a panic.\n") != true {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	} else if tla.lastContext.Error().Error() != "panic: something broke" {
+		t.Fatalf("Error not correct: [%s]", tla.lastContext.Error())
+	}
+
+	caller := tla.lastContext.Caller()
+	if path.Base(caller.File) != "recover_test.go" || caller.Line != expectedLine {
+		t.Fatalf("Caller should be the panic site: %v (%d)", caller, expectedLine)
+	}
+}
+
+func TestLogger_RecoverAndRepanic(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+	original := e.New("an error happened")
+
+	var state interface{}
+
+	func() {
+		defer func() {
+			state = recover()
+		}()
+
+		defer l.RecoverAndRepanic(nil)
+
+		panic(original)
+	}()
+
+	if tla.errorTriggered != true {
+		t.Fatalf("Panic not logged.")
+	}
+
+	err, ok := state.(*errors.Error)
+	if ok != true {
+		t.Fatalf("Panic should be stack-wrapped: [%T]", state)
+	} else if Is(err, original) != true {
+		t.Fatalf("Original error not found in the chain.")
+	}
+}
+
+func TestLogger_Panicf__Filtered(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+	AddExcludeFilter("callerTest")
+
+	original := e.New("an error happened")
+
+	defer func() {
+		state := recover()
+
+		if err, ok := state.(error); ok != true {
+			t.Fatalf("Panic value not an error: [%v]", state)
+		} else if Is(err, original) != true {
+			t.Fatalf("Original error not panicked: [%v]", err)
+		} else if tla.errorTriggered != false {
+			t.Fatalf("Message should have been filtered.")
+		}
+	}()
+
+	l.Panicf(nil, original, "Panic message")
+}
@@ -0,0 +1,183 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// registry is a snapshot of the global configuration, filters, and adapters.
+// A published registry is never modified. Changes are made by copying the
+// current registry, modifying the copy, and publishing that, so that loggers
+// can read it from any goroutine without locking.
+type registry struct {
+	// generation is incremented with every change so that existing loggers
+	// know to reconfigure themselves.
+	generation uint64
+
+	// Alternative format.
+	format string
+
+	// Alternative adapter.
+	defaultAdapterName string
+
+	// Alternative level at which to display log-items
+	levelName LogLevelName
+
+	// Configuration-driven comma-separated list of nouns to include.
+	includeNouns string
+
+	// Configuration-driven comma-separated list of nouns to exclude.
+	excludeNouns string
+
+	// excludeBypassLevelName is the level at which to disregard exclusion (if
+	// the severity of a message meets or exceed this, always display).
+	excludeBypassLevelName LogLevelName
+
+	// Configuration-driven comma-separated list of "noun=level" pairs that
+	// override the level for those nouns and the nouns beneath them.
+	nounLevels string
+
+	configurationLoaded bool
+
+	// captureCaller indicates whether the call-site of each message is
+	// recorded.
+	captureCaller bool
+
+	// clock timestamps messages and clockStart is what elapsed times are
+	// measured from.
+	clock      Clock
+	clockStart time.Time
+
+	// templateFuncs are the built-in and registered format-template functions.
+	// templateFuncsVersion is incremented whenever they change so that the
+	// template is reparsed.
+	templateFuncs        template.FuncMap
+	templateFuncsVersion uint64
+
+	// includeFilters and excludeFilters are the filters added with
+	// AddIncludeFilter and AddExcludeFilter.
+	includeFilters map[string]nounPattern
+	excludeFilters map[string]nounPattern
+
+	// configIncludeFilters and configExcludeFilters are parsed from
+	// includeNouns and excludeNouns. They are kept apart from the ones above
+	// so that loading a configuration doesn't drop those. They are replaced
+	// rather than modified, so they're shared between copies.
+	configIncludeFilters map[string]nounPattern
+	configExcludeFilters map[string]nounPattern
+
+	// levelsByName and levelNamesByLevel include the built-in levels and any
+	// registered with RegisterLevel.
+	levelsByName      map[LogLevelName]LogLevel
+	levelNamesByLevel map[LogLevel]LogLevelName
+
+	// nounLevelOverrides are the programmatic per-noun level overrides. These
+	// take precedence over the configured ones.
+	nounLevelOverrides map[string]LogLevel
+
+	adapters map[string]LogAdapter
+}
+
+var (
+	// initialRegistry is used until the first change is published.
+	initialRegistry = &registry{
+		format:               defaultFormat,
+		levelName:            LogLevelName(strings.ToLower(string(defaultLevelName))),
+		includeFilters:       make(map[string]nounPattern),
+		excludeFilters:       make(map[string]nounPattern),
+		configIncludeFilters: make(map[string]nounPattern),
+		configExcludeFilters: make(map[string]nounPattern),
+		levelsByName:         levelNameMap,
+		levelNamesByLevel:    levelNameMapR,
+		nounLevelOverrides:   make(map[string]LogLevel),
+		adapters:             make(map[string]LogAdapter),
+		clock:                SystemClock{},
+		clockStart:           processStart,
+		templateFuncs:        make(template.FuncMap),
+	}
+
+	currentRegistry atomic.Value
+	registryMutex   sync.Mutex
+)
+
+// newBaseRegistry returns a registry with only the built-in state (the levels,
+// template functions, and system clock). Hand-built configurations are
+// restored on top of it.
+func newBaseRegistry() *registry {
+	r := initialRegistry.clone()
+
+	for name, fn := range builtinTemplateFuncs {
+		r.templateFuncs[name] = fn
+	}
+
+	return r
+}
+
+// loadRegistry returns the current registry. The caller must not modify it.
+func loadRegistry() *registry {
+	if r, ok := currentRegistry.Load().(*registry); ok == true {
+		return r
+	}
+
+	return initialRegistry
+}
+
+// updateRegistry passes a copy of the current registry to the callback and
+// publishes it once the callback returns. Updates are serialized. If the
+// callback panics, nothing is published.
+func updateRegistry(cb func(r *registry)) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	r := loadRegistry().clone()
+	r.generation++
+
+	cb(r)
+
+	currentRegistry.Store(r)
+}
+
+// clone returns a copy of the registry that can be modified.
+func (r *registry) clone() *registry {
+	copied := *r
+
+	copied.includeFilters = make(map[string]nounPattern, len(r.includeFilters))
+	for noun, np := range r.includeFilters {
+		copied.includeFilters[noun] = np
+	}
+
+	copied.excludeFilters = make(map[string]nounPattern, len(r.excludeFilters))
+	for noun, np := range r.excludeFilters {
+		copied.excludeFilters[noun] = np
+	}
+
+	copied.levelsByName = make(map[LogLevelName]LogLevel, len(r.levelsByName))
+	for levelName, level := range r.levelsByName {
+		copied.levelsByName[levelName] = level
+	}
+
+	copied.levelNamesByLevel = make(map[LogLevel]LogLevelName, len(r.levelNamesByLevel))
+	for level, levelName := range r.levelNamesByLevel {
+		copied.levelNamesByLevel[level] = levelName
+	}
+
+	copied.nounLevelOverrides = make(map[string]LogLevel, len(r.nounLevelOverrides))
+	for noun, level := range r.nounLevelOverrides {
+		copied.nounLevelOverrides[noun] = level
+	}
+
+	copied.templateFuncs = make(template.FuncMap, len(r.templateFuncs))
+	for name, fn := range r.templateFuncs {
+		copied.templateFuncs[name] = fn
+	}
+
+	copied.adapters = make(map[string]LogAdapter, len(r.adapters))
+	for name, la := range r.adapters {
+		copied.adapters[name] = la
+	}
+
+	return &copied
+}
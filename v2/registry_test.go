@@ -0,0 +1,184 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestUpdateRegistry(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	original := loadRegistry()
+
+	updateRegistry(func(r *registry) {
+		r.format = "changed"
+		r.includeFilters["updateRegistryTest"] = newNounPattern("updateRegistryTest")
+	})
+
+	defer RemoveIncludeFilter("updateRegistryTest")
+
+	updated := loadRegistry()
+
+	if updated.generation != original.generation+1 {
+		t.Fatalf("Generation not incremented: (%d) -> (%d)", original.generation, updated.generation)
+	} else if updated.format != "changed" {
+		t.Fatalf("Update not published.")
+	} else if original.format == "changed" {
+		t.Fatalf("Original registry was modified.")
+	} else if _, found := original.includeFilters["updateRegistryTest"]; found == true {
+		t.Fatalf("Original registry's filters were modified.")
+	}
+}
+
+func TestUpdateRegistry__Panic(t *testing.T) {
+	original := loadRegistry()
+
+	func() {
+		defer func() {
+			if state := recover(); state == nil {
+				t.Fatalf("Expected panic.")
+			}
+		}()
+
+		updateRegistry(func(r *registry) {
+			r.format = "changed"
+			panic(fmt.Errorf("failed"))
+		})
+	}()
+
+	if loadRegistry() != original {
+		t.Fatalf("Registry should not have been published after a panic.")
+	}
+
+	// Make sure that the lock was released.
+	updateRegistry(func(r *registry) {})
+}
+
+// Run with -race. Logs from several goroutines while the configuration,
+// filters, noun-levels, and adapters are being changed from others.
+func TestRegistry__ConcurrentLoggingAndReconfiguration(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+	defer ClearAdapters()
+
+	AddAdapter("concurrent0", newGatedLogAdapter(false))
+
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			l := NewLogger(fmt.Sprintf("concurrent.logger%d", i))
+			child := l.With(NewField("index", i))
+
+			for j := 0; j < 200; j++ {
+				l.Debugf(nil, "Debug message (%d)", j)
+				child.Infof(nil, "Info message (%d)", j)
+				l.Warningf(nil, "Warning message (%d)", j)
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for j := 0; j < 50; j++ {
+			scp := NewStaticConfigurationProvider()
+
+			if j%2 == 0 {
+				scp.SetLevelName(levelNameInfo)
+				scp.SetFormat("{{.Noun}} {{.Message}}")
+			} else {
+				scp.SetLevelName(levelNameDebug)
+				scp.SetNounLevels("concurrent.logger1=warning")
+			}
+
+			LoadConfiguration(scp)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for j := 0; j < 50; j++ {
+			AddExcludeFilter("concurrent.logger2")
+			AddIncludeFilter("concurrent.**")
+			SetNounLevel("concurrent.logger3", LevelError)
+
+			RemoveExcludeFilter("concurrent.logger2")
+			RemoveIncludeFilter("concurrent.**")
+			RemoveNounLevel("concurrent.logger3")
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for j := 1; j < 50; j++ {
+			adapterName := fmt.Sprintf("concurrent%d", j)
+
+			AddAdapter(adapterName, newGatedLogAdapter(false))
+			SetDefaultAdapterName(adapterName)
+
+			_ = GetDefaultAdapterName()
+			_ = getConfigDump()
+		}
+	}()
+
+	wg.Wait()
+}
+
+// Run with -race. Loggers that are first used concurrently must configure
+// themselves exactly once per change.
+func TestLogger_doConfigure__Concurrent(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+	defer ClearAdapters()
+
+	gla := newGatedLogAdapter(false)
+	AddAdapter("test", gla)
+
+	l := NewLogger("logTest")
+
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			l.Infof(nil, "Info message")
+		}()
+	}
+
+	wg.Wait()
+
+	if len(gla.Messages()) != 8 {
+		t.Fatalf("Not all messages were logged: (%d)", len(gla.Messages()))
+	}
+}
@@ -0,0 +1,263 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+const (
+	// SlogNounKey is the attribute key that SlogHandler takes the noun from
+	// (rather than treating it as a field) and that SlogLogAdapter writes the
+	// noun to.
+	SlogNounKey = "noun"
+
+	// SlogErrorKey is the attribute key that SlogLogAdapter writes the logged
+	// error to.
+	SlogErrorKey = "error"
+)
+
+// SlogHandler is a slog.Handler that logs records through this package (and,
+// so, the configured levels, filters, format, and adapter). Attributes become
+// fields (attributes in groups are named "group.key") except for a top-level
+// SlogNounKey attribute, which sets the noun.
+type SlogHandler struct {
+	noun        string
+	adapterName string
+	fields      Fields
+	groupPrefix string
+
+	// loggers are shared by handlers derived with WithAttrs() and WithGroup().
+	loggers *sync.Map
+}
+
+// NewSlogHandler returns a SlogHandler that logs with the given default noun
+// to the adapter with the given name (or the default adapter if empty).
+func NewSlogHandler(noun string, adapterName string) *SlogHandler {
+	return &SlogHandler{
+		noun:        noun,
+		adapterName: adapterName,
+		loggers:     new(sync.Map),
+	}
+}
+
+// Enabled returns whether the configured level for the handler's noun allows
+// the level. Since the noun can also be set per-record, this only considers
+// the handler's noun.
+func (sh *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if IsConfigurationLoaded() == false {
+		return false
+	}
+
+	ls := sh.logger(sh.noun).doConfigure(false)
+
+	return ls.la != nil && slogToLevel(level) >= ls.systemLevel
+}
+
+// Handle logs the record.
+func (sh *SlogHandler) Handle(ctx context.Context, record slog.Record) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	noun := sh.noun
+
+	fields := make(Fields, len(sh.fields), len(sh.fields)+record.NumAttrs())
+	copy(fields, sh.fields)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if sh.groupPrefix == "" && attr.Key == SlogNounKey {
+			noun = attr.Value.Resolve().String()
+			return true
+		}
+
+		fields = appendSlogAttr(fields, sh.groupPrefix, attr)
+		return true
+	})
+
+	l := sh.logger(noun)
+	ls := l.doConfigure(false)
+
+	cs := callerSource{
+		pc: record.PC,
+	}
+
+	l.logFrom(ls, ctx, cs, slogToLevel(record.Level), nil, fields, "%s", []interface{}{record.Message})
+
+	return nil
+}
+
+// WithAttrs returns a handler that adds the attributes to every record.
+func (sh *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	copied := *sh
+	copied.fields = make(Fields, len(sh.fields), len(sh.fields)+len(attrs))
+	copy(copied.fields, sh.fields)
+
+	for _, attr := range attrs {
+		if sh.groupPrefix == "" && attr.Key == SlogNounKey {
+			copied.noun = attr.Value.Resolve().String()
+			continue
+		}
+
+		copied.fields = appendSlogAttr(copied.fields, sh.groupPrefix, attr)
+	}
+
+	return &copied
+}
+
+// WithGroup returns a handler that qualifies the names of subsequent
+// attributes with the group.
+func (sh *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return sh
+	}
+
+	copied := *sh
+	copied.groupPrefix = sh.groupPrefix + name + "."
+
+	return &copied
+}
+
+// logger returns the (cached) logger for the noun.
+func (sh *SlogHandler) logger(noun string) *Logger {
+	if l, found := sh.loggers.Load(noun); found == true {
+		return l.(*Logger)
+	}
+
+	l, _ := sh.loggers.LoadOrStore(noun, NewLoggerWithAdapterName(noun, sh.adapterName))
+	return l.(*Logger)
+}
+
+// appendSlogAttr appends the attribute as a field, flattening groups.
+func appendSlogAttr(fields Fields, prefix string, attr slog.Attr) Fields {
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+
+		for _, groupAttr := range value.Group() {
+			fields = appendSlogAttr(fields, groupPrefix, groupAttr)
+		}
+
+		return fields
+	}
+
+	if attr.Equal(slog.Attr{}) == true {
+		return fields
+	}
+
+	return append(fields, NewField(prefix+attr.Key, value.Any()))
+}
+
+// SlogLogAdapter is a LogAdapter that writes to a slog.Handler. The noun, the
+// error (if any), and the fields are written as attributes.
+type SlogLogAdapter struct {
+	h slog.Handler
+}
+
+// NewSlogLogAdapter returns a new SlogLogAdapter.
+func NewSlogLogAdapter(h slog.Handler) *SlogLogAdapter {
+	return &SlogLogAdapter{
+		h: h,
+	}
+}
+
+// Tracef logs a trace message.
+func (sla *SlogLogAdapter) Tracef(lc *LogContext, message *string) error {
+	return sla.write(lc, LevelTrace)
+}
+
+// Debugf logs a debugging message.
+func (sla *SlogLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return sla.write(lc, LevelDebug)
+}
+
+// Infof logs an info message.
+func (sla *SlogLogAdapter) Infof(lc *LogContext, message *string) error {
+	return sla.write(lc, LevelInfo)
+}
+
+// Warningf logs a warning message.
+func (sla *SlogLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return sla.write(lc, LevelWarning)
+}
+
+// Errorf logs an error message.
+func (sla *SlogLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return sla.write(lc, LevelError)
+}
+
+// Criticalf logs a critical message.
+func (sla *SlogLogAdapter) Criticalf(lc *LogContext, message *string) error {
+	return sla.write(lc, LevelCritical)
+}
+
+func (sla *SlogLogAdapter) write(lc *LogContext, methodLevel LogLevel) error {
+	ctx := lc.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := levelToSlog(messageLevel(lc, methodLevel))
+	if sla.h.Enabled(ctx, level) == false {
+		return nil
+	}
+
+	record := slog.NewRecord(lc.Time().Time, level, lc.Message(), 0)
+	record.AddAttrs(slog.String(SlogNounKey, lc.Noun()))
+
+	if lcErr := lc.Error(); lcErr != nil {
+		record.AddAttrs(slog.Any(SlogErrorKey, lcErr))
+	}
+
+	for _, f := range lc.Fields() {
+		record.AddAttrs(slog.Any(f.Name, f.Value))
+	}
+
+	return sla.h.Handle(ctx, record)
+}
+
+// slogToLevel returns the level for the slog level. Levels between the
+// standard slog levels take the lower of the two.
+func slogToLevel(level slog.Level) LogLevel {
+	if level < slog.LevelDebug {
+		return LevelTrace
+	} else if level < slog.LevelInfo {
+		return LevelDebug
+	} else if level < slog.LevelWarn {
+		return LevelInfo
+	} else if level < slog.LevelError {
+		return LevelWarning
+	} else if level < slog.LevelError+4 {
+		return LevelError
+	}
+
+	return LevelCritical
+}
+
+// levelToSlog returns the slog level for the level. Trace and critical are
+// four below debug and four above error, respectively.
+func levelToSlog(level LogLevel) slog.Level {
+	switch adapterLevel(level) {
+	case LevelTrace:
+		return slog.LevelDebug - 4
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	}
+
+	return slog.LevelError + 4
+}
@@ -0,0 +1,183 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	e "errors"
+	"log/slog"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	sl := slog.New(NewSlogHandler("slogTest", "test")).With("tenant", 11).WithGroup("request")
+
+	sl.Warn("Warning message", "id", 22, slog.Group("user", "name", "joe"))
+
+	if tla.warningTriggered != true {
+		t.Fatalf("Warning not forwarded.")
+	} else if tla.lastContext.Noun() != "slogTest" {
+		t.Fatalf("Noun not correct: [%s]", tla.lastContext.Noun())
+	}
+
+	expectedFields := Fields{
+		NewField("tenant", int64(11)),
+		NewField("request.id", int64(22)),
+		NewField("request.user.name", "joe"),
+	}
+
+	if reflect.DeepEqual(tla.lastContext.Fields(), expectedFields) != true {
+		t.Fatalf("Fields not correct: %v", tla.lastContext.Fields())
+	} else if tla.lastMessage != "slogTest: [WARNING]  Warning message tenant=11 request.id=22 request.user.name=joe" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+
+	slog.New(NewSlogHandler("slogTest", "test")).Info("Info message", SlogNounKey, "other")
+
+	if tla.lastContext.Noun() != "other" {
+		t.Fatalf("Noun not taken from the attribute: [%s]", tla.lastContext.Noun())
+	} else if len(tla.lastContext.Fields()) != 0 {
+		t.Fatalf("Noun should not be a field: %v", tla.lastContext.Fields())
+	}
+}
+
+func TestSlogHandler__Levels(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameInfo)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	sh := NewSlogHandler("slogTest", "test")
+	ctx := context.Background()
+
+	if sh.Enabled(ctx, slog.LevelDebug) != false {
+		t.Fatalf("Debug should not be enabled.")
+	} else if sh.Enabled(ctx, slog.LevelInfo) != true {
+		t.Fatalf("Info should be enabled.")
+	}
+
+	sl := slog.New(sh)
+
+	sl.Debug("Debug message")
+
+	if tla.debugTriggered != false {
+		t.Fatalf("Debug message should be filtered.")
+	}
+
+	sl.Log(ctx, slog.LevelError+4, "Critical message")
+
+	if tla.lastContext.Level() != LevelCritical {
+		t.Fatalf("Level not mapped correctly: (%d)", tla.lastContext.Level())
+	}
+}
+
+func TestSlogHandler__Caller(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, _ := setupCallerTest()
+
+	slog.New(NewSlogHandler("slogTest", "test")).Info("Info message")
+	expectedLine := currentLine() - 1
+
+	caller := tla.lastContext.Caller()
+	if path.Base(caller.File) != "slog_test.go" || caller.Line != expectedLine {
+		t.Fatalf("Caller not correct: %v", caller)
+	}
+}
+
+func TestSlogLogAdapter(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameTrace)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+
+	th := slog.NewTextHandler(b, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	})
+
+	AddAdapter("slog", NewSlogLogAdapter(th))
+
+	l := NewLoggerWithAdapterName("slogTest", "slog").With(NewField("tenant", 11))
+
+	l.Debugf(nil, "Debug message")
+
+	if b.Len() != 0 {
+		t.Fatalf("Debug message should be filtered by the handler: [%s]", b.String())
+	}
+
+	l.Warningf(nil, "Warning message")
+
+	if b.String() != "level=WARN msg=\"Warning message\" noun=slogTest tenant=11\n" {
+		t.Fatalf("Record not correct: [%s]", b.String())
+	}
+
+	b.Reset()
+
+	l.Errorf(nil, e.New("an error happened"), "Error message")
+
+	if strings.HasPrefix(b.String(), "level=ERROR msg=\"Error message\" noun=slogTest error=\"an error happened\" tenant=11") != true {
+		t.Fatalf("Record not correct: [%s]", b.String())
+	}
+}
+
+func TestSlogLevels(t *testing.T) {
+	cases := map[LogLevel]slog.Level{
+		LevelTrace:    slog.LevelDebug - 4,
+		LevelDebug:    slog.LevelDebug,
+		LevelInfo:     slog.LevelInfo,
+		LevelWarning:  slog.LevelWarn,
+		LevelError:    slog.LevelError,
+		LevelCritical: slog.LevelError + 4,
+	}
+
+	for level, expected := range cases {
+		if actual := levelToSlog(level); actual != expected {
+			t.Fatalf("Level (%d) not mapped correctly: (%d)", level, actual)
+		} else if roundTripped := slogToLevel(actual); roundTripped != level {
+			t.Fatalf("Slog level (%d) not mapped correctly: (%d)", actual, roundTripped)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package log
+
+import (
+	golog "log"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// stdlibOriginal holds the *golog.Logger that writes to wherever the
+	// standard library's logger wrote before it was redirected (or nil).
+	stdlibOriginal      atomic.Value
+	stdlibRedirectMutex sync.Mutex
+)
+
+// StdlibWriter is an io.Writer that logs each line written to it. This is
+// meant to be given to the standard library's log package (see
+// NewStdlibLogger and RedirectStdlibLog) so that its output goes through the
+// filters, levels, format, and adapter of a Logger.
+type StdlibWriter struct {
+	l     *Logger
+	level LogLevel
+}
+
+// NewStdlibWriter returns a writer that logs each line to the logger at the
+// given level.
+func NewStdlibWriter(l *Logger, level LogLevel) *StdlibWriter {
+	return &StdlibWriter{
+		l:     l,
+		level: level,
+	}
+}
+
+// Write logs each non-empty line.
+func (sw *StdlibWriter) Write(p []byte) (n int, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = Wrap(state.(error))
+		}
+	}()
+
+	ls := sw.l.doConfigure(false)
+
+	var cs callerSource
+	if ls.registry.captureCaller == true {
+		cs.pc = findStdlibCallerPC()
+	}
+
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+
+		sw.l.logFrom(ls, nil, cs, sw.level, nil, nil, "%s", []interface{}{line})
+	}
+
+	return len(p), nil
+}
+
+// NewStdlibLogger returns a standard library logger that logs each line to the
+// logger at the given level. This is useful for APIs that take one (e.g.
+// http.Server.ErrorLog).
+func NewStdlibLogger(l *Logger, level LogLevel) *golog.Logger {
+	return golog.New(NewStdlibWriter(l, level), "", 0)
+}
+
+// RedirectStdlibLog sends everything written via the standard library's global
+// logger to the logger at the given level. The global logger's prefix and flags
+// are cleared since the format template applies instead. Call the returned
+// function to restore the original output, prefix, and flags.
+//
+// Since ConsoleLogAdapter writes via the global logger by default, it writes
+// to the original output (with the original prefix and flags) while the
+// redirect is installed rather than logging back into itself.
+func RedirectStdlibLog(l *Logger, level LogLevel) (restore func()) {
+	stdlibRedirectMutex.Lock()
+	defer stdlibRedirectMutex.Unlock()
+
+	originalWriter := golog.Writer()
+	originalPrefix := golog.Prefix()
+	originalFlags := golog.Flags()
+
+	previous := stdlibOriginal.Load()
+
+	// If already redirected, keep writing the console to the real output.
+	if previous == nil || previous.(*golog.Logger) == nil {
+		stdlibOriginal.Store(golog.New(originalWriter, originalPrefix, originalFlags))
+	}
+
+	golog.SetOutput(NewStdlibWriter(l, level))
+	golog.SetPrefix("")
+	golog.SetFlags(0)
+
+	return func() {
+		stdlibRedirectMutex.Lock()
+		defer stdlibRedirectMutex.Unlock()
+
+		golog.SetOutput(originalWriter)
+		golog.SetPrefix(originalPrefix)
+		golog.SetFlags(originalFlags)
+
+		if previous == nil {
+			previous = (*golog.Logger)(nil)
+		}
+
+		stdlibOriginal.Store(previous)
+	}
+}
+
+// stdlibConsoleLogger returns the logger that the console adapter should use
+// instead of the global logger or nil if the global logger isn't redirected.
+func stdlibConsoleLogger() *golog.Logger {
+	original, _ := stdlibOriginal.Load().(*golog.Logger)
+	return original
+}
+
+// findStdlibCallerPC returns the program-counter of the first frame above
+// StdlibWriter.Write() that is outside of the standard library's log package.
+func findStdlibCallerPC() uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+
+		pkg, _ := splitFunctionName(frame.Function)
+		if pkg != "log" {
+			// Like the ones from runtime.Callers, the PC that we return is
+			// expected to be the return address (one past the call).
+			return frame.PC + 1
+		}
+
+		if more == false {
+			return 0
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package log
+
+import (
+	"bytes"
+	golog "log"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestNewStdlibLogger(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("stdlibTest", "test")
+	sl := NewStdlibLogger(l, LevelWarning)
+
+	sl.Printf("Warning message")
+
+	if tla.warningTriggered != true {
+		t.Fatalf("Line not logged at the given level.")
+	} else if tla.lastMessage != "stdlibTest: [WARNING]  Warning message" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	}
+
+	ClearAdapters()
+
+	gla := newGatedLogAdapter(false)
+	AddAdapter("test", gla)
+
+	sl.Print("line one\n\nline two\r\n")
+
+	messages := gla.Messages()
+
+	if len(messages) != 2 {
+		t.Fatalf("Expected one message per line: %v", messages)
+	} else if messages[0] != "stdlibTest: [WARNING]  line one" || messages[1] != "stdlibTest: [WARNING]  line two" {
+		t.Fatalf("Messages not correct: %v", messages)
+	}
+}
+
+func TestNewStdlibLogger__Filtered(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	AddExcludeFilter("stdlibTest")
+	defer RemoveExcludeFilter("stdlibTest")
+
+	l := NewLoggerWithAdapterName("stdlibTest", "test")
+	NewStdlibLogger(l, LevelInfo).Printf("Info message")
+
+	if tla.infoTriggered != false {
+		t.Fatalf("Excluded noun should be filtered.")
+	}
+}
+
+func TestNewStdlibLogger__Caller(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupCallerTest()
+
+	NewStdlibLogger(l, LevelInfo).Printf("Info message")
+	expectedLine := currentLine() - 1
+
+	caller := tla.lastContext.Caller()
+	if path.Base(caller.File) != "stdlib_test.go" || caller.Line != expectedLine {
+		t.Fatalf("Caller not correct: %v", caller)
+	}
+}
+
+func TestRedirectStdlibLog(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	// The console adapter writes via the global logger, which we're about to
+	// redirect into the console adapter.
+	AddAdapter("console", NewConsoleLogAdapter())
+
+	b := new(bytes.Buffer)
+
+	golog.SetOutput(b)
+	golog.SetPrefix("original ")
+	golog.SetFlags(0)
+
+	defer func() {
+		golog.SetOutput(os.Stderr)
+		golog.SetPrefix("")
+		golog.SetFlags(golog.LstdFlags)
+	}()
+
+	l := NewLoggerWithAdapterName("stdlibTest", "console")
+	restore := RedirectStdlibLog(l, LevelInfo)
+
+	golog.Printf("Info message")
+
+	if b.String() != "original stdlibTest: [INFO]  Info message\n" {
+		t.Fatalf("Redirected message not correct: [%s]", b.String())
+	}
+
+	restore()
+
+	b.Reset()
+	golog.Printf("Direct message")
+
+	if b.String() != "original Direct message\n" {
+		t.Fatalf("Output not restored: [%s]", b.String())
+	} else if stdlibConsoleLogger() != nil {
+		t.Fatalf("Console should use the global logger again.")
+	}
+}
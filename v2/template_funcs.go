@@ -0,0 +1,188 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+)
+
+var (
+	// levelColors are the ANSI color sequences for each built-in level. Other
+	// levels use the color of the built-in level that their messages are
+	// routed to.
+	levelColors = map[LogLevel]string{
+		LevelTrace:    "\x1b[90m",
+		LevelDebug:    "\x1b[36m",
+		LevelInfo:     "\x1b[32m",
+		LevelWarning:  "\x1b[33m",
+		LevelError:    "\x1b[31m",
+		LevelCritical: "\x1b[1;31m",
+	}
+
+	// builtinTemplateFuncs are available to every format template.
+	builtinTemplateFuncs = template.FuncMap{
+		"pad":      templatePad,
+		"padLeft":  templatePadLeft,
+		"truncate": templateTruncate,
+		"upper":    templateUpper,
+		"lower":    templateLower,
+		"json":     templateJSON,
+		"color":    templateColor,
+		"default":  templateDefault,
+		"indent":   templateIndent,
+	}
+)
+
+func init() {
+	// These are added here rather than in initialRegistry since "color" looks
+	// up levels in the registry.
+	updateRegistry(func(r *registry) {
+		for name, fn := range builtinTemplateFuncs {
+			r.templateFuncs[name] = fn
+		}
+	})
+}
+
+// RegisterTemplateFunc makes an additional function available to the format
+// template. It follows the rules of text/template's Funcs() and replaces any
+// existing function (including the built-in ones) with the same name. Loggers
+// pick it up the next time they log.
+func RegisterTemplateFunc(name string, fn interface{}) {
+	updateRegistry(func(r *registry) {
+		// This panics if the function is not valid.
+		template.New("").Funcs(template.FuncMap{name: fn})
+
+		r.templateFuncs[name] = fn
+		r.templateFuncsVersion++
+	})
+}
+
+// colorize wraps the string in the ANSI color of the given level.
+func colorize(level LogLevel, s string) string {
+	return levelColors[adapterLevel(level)] + s + ansiReset
+}
+
+// templateString renders a template value as a string. MessageContext has
+// several pointer fields, so those are dereferenced first.
+func templateString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+
+		return *v
+	case LogLevelName:
+		return string(v)
+	case *LogLevelName:
+		if v == nil {
+			return ""
+		}
+
+		return string(*v)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// templatePad pads the value with spaces on the right to the given width.
+func templatePad(width int, value interface{}) string {
+	s := templateString(value)
+
+	if n := utf8.RuneCountInString(s); n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
+
+// templatePadLeft pads the value with spaces on the left to the given width.
+func templatePadLeft(width int, value interface{}) string {
+	s := templateString(value)
+
+	if n := utf8.RuneCountInString(s); n < width {
+		s = strings.Repeat(" ", width-n) + s
+	}
+
+	return s
+}
+
+// templateTruncate cuts the value down to the given width.
+func templateTruncate(width int, value interface{}) string {
+	s := templateString(value)
+
+	if utf8.RuneCountInString(s) > width {
+		s = string([]rune(s)[:width])
+	}
+
+	return s
+}
+
+func templateUpper(value interface{}) string {
+	return strings.ToUpper(templateString(value))
+}
+
+func templateLower(value interface{}) string {
+	return strings.ToLower(templateString(value))
+}
+
+// templateJSON renders the value as a JSON string.
+func templateJSON(value interface{}) (string, error) {
+	encoded, err := json.Marshal(templateString(value))
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// templateColor wraps the value in the ANSI color of the level with the given
+// name (e.g. `{{color .Level .Message}}`).
+func templateColor(levelName interface{}, value interface{}) string {
+	s := templateString(value)
+
+	normalized := LogLevelName(strings.ToLower(templateString(levelName)))
+
+	level, found := loadRegistry().levelsByName[normalized]
+	if found == false {
+		return s
+	}
+
+	return colorize(level, s)
+}
+
+// templateDefault returns the value or, if it is empty, the default.
+func templateDefault(defaultValue interface{}, value interface{}) interface{} {
+	if value == nil {
+		return defaultValue
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() == false {
+		rv = rv.Elem()
+	}
+
+	if rv.IsZero() == true {
+		return defaultValue
+	}
+
+	return value
+}
+
+// templateIndent indents every line after the first by the given number of
+// spaces so that multi-line values (e.g. stacks) line up.
+func templateIndent(spaces int, value interface{}) string {
+	s := templateString(value)
+	return strings.Replace(s, "\n", "\n"+strings.Repeat(" ", spaces), -1)
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func setupTemplateTest(format string) (tla *testLogAdapter, l *Logger) {
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameDebug)
+	scp.SetFormat(format)
+
+	LoadConfiguration(scp)
+
+	ClearAdapters()
+
+	tla = newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l = NewLoggerWithAdapterName("templateTest", "test")
+
+	return tla, l
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	cases := map[string]string{
+		`[{{pad 8 .Level}}]`:               "[WARNING ]",
+		`[{{padLeft 14 .Noun}}]`:           "[  templateTest]",
+		`[{{truncate 4 .Noun}}]`:           "[temp]",
+		`[{{truncate 40 .Noun}}]`:          "[templateTest]",
+		`{{lower .Level}} {{upper .Noun}}`: "warning TEMPLATETEST",
+		`{{json .Message}}`:                `"line \"one\"\nline two"`,
+		`{{indent 2 .Message}}`:            "line \"one\"\n  line two",
+		`{{color .Level .Noun}}`:           "\x1b[33mtemplateTest" + ansiReset,
+		`{{color "unknown" .Noun}}`:        "templateTest",
+		`{{default "none" .Fields}}`:       "none",
+		`{{default "none" .Noun}}`:         "templateTest",
+	}
+
+	for format, expected := range cases {
+		tla, l := setupTemplateTest(format)
+
+		l.Warningf(nil, "line \"one\"\nline two")
+
+		if tla.lastMessage != expected {
+			t.Fatalf("Format [%s] not rendered correctly: [%s] != [%s]", format, tla.lastMessage, expected)
+		}
+	}
+}
+
+func TestRegisterTemplateFunc(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tla, l := setupTemplateTest(`{{shout .Message}}`)
+
+	defer func() {
+		if state := recover(); state == nil {
+			t.Fatalf("Expected panic for unknown function.")
+		}
+	}()
+
+	RegisterTemplateFunc("shout", func(s *string) string {
+		return strings.ToUpper(*s) + "!"
+	})
+
+	l.Infof(nil, "hello")
+
+	if tla.lastMessage != "HELLO!" {
+		t.Fatalf("Registered function not used: [%s]", tla.lastMessage)
+	}
+
+	// Functions that don't satisfy text/template are rejected.
+	RegisterTemplateFunc("invalid", 123)
+}
+
+func TestTemplateDefault(t *testing.T) {
+	empty := ""
+	value := "value"
+
+	if templateDefault("x", nil) != "x" {
+		t.Fatalf("Nil not defaulted.")
+	} else if templateDefault("x", &empty) != "x" {
+		t.Fatalf("Empty string pointer not defaulted.")
+	} else if templateDefault("x", &value) != &value {
+		t.Fatalf("Non-empty string pointer should be returned.")
+	} else if templateDefault("x", 0) != "x" {
+		t.Fatalf("Zero not defaulted.")
+	}
+}
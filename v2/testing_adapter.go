@@ -0,0 +1,112 @@
+package log
+
+import (
+	"strings"
+)
+
+const (
+	// TestAdapterName is the name of the default adapter installed by
+	// InstallTestLogging. The capture and testing adapters that it forwards to
+	// are registered as TestAdapterName + ".capture" and + ".log".
+	TestAdapterName = "test"
+)
+
+// TestingTB is the part of testing.TB that the testing helpers need. It is
+// satisfied by *testing.T and *testing.B.
+type TestingTB interface {
+	Helper()
+	Log(args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// TestingLogAdapter writes messages to a test's log (e.g. t.Log()) so that
+// they are shown with the test that produced them (and only if it fails or is
+// run verbosely).
+type TestingLogAdapter struct {
+	tb TestingTB
+}
+
+// NewTestingLogAdapter returns a new TestingLogAdapter.
+func NewTestingLogAdapter(tb TestingTB) *TestingLogAdapter {
+	return &TestingLogAdapter{
+		tb: tb,
+	}
+}
+
+// Debugf logs a debugging message.
+func (tla *TestingLogAdapter) Debugf(lc *LogContext, message *string) error {
+	return tla.write(message)
+}
+
+// Infof logs an info message.
+func (tla *TestingLogAdapter) Infof(lc *LogContext, message *string) error {
+	return tla.write(message)
+}
+
+// Warningf logs a warning message.
+func (tla *TestingLogAdapter) Warningf(lc *LogContext, message *string) error {
+	return tla.write(message)
+}
+
+// Errorf logs an error message.
+func (tla *TestingLogAdapter) Errorf(lc *LogContext, message *string) error {
+	return tla.write(message)
+}
+
+func (tla *TestingLogAdapter) write(message *string) error {
+	tla.tb.Helper()
+	tla.tb.Log(*message)
+
+	return nil
+}
+
+// InstallTestLogging replaces the global configuration with one that logs at
+// the given level (with the default format and no filters) to both a
+// CaptureLogAdapter, which is returned, and the test's log. Call restore (e.g.
+// deferred) to put the previous configuration, filters, and adapters back.
+func InstallTestLogging(tb TestingTB, levelName LogLevelName) (cla *CaptureLogAdapter, restore func()) {
+	tb.Helper()
+
+	saved := Snapshot()
+
+	cla = NewCaptureLogAdapter()
+	tla := NewTestingLogAdapter(tb)
+
+	mla := NewMultiLogAdapter()
+	mla.AddChild(TestAdapterName+".capture", LevelTrace)
+	mla.AddChild(TestAdapterName+".log", LevelTrace)
+
+	updateRegistry(func(r *registry) {
+		levelName = LogLevelName(strings.ToLower(string(levelName)))
+		if _, found := r.levelsByName[levelName]; found == false {
+			Panicf("log-level not valid: [%s]", levelName)
+		}
+
+		r.format = defaultFormat
+		r.levelName = levelName
+		r.includeNouns = ""
+		r.excludeNouns = ""
+		r.excludeBypassLevelName = ""
+		r.nounLevels = ""
+		r.includeFilters = make(map[string]nounPattern)
+		r.excludeFilters = make(map[string]nounPattern)
+		r.configIncludeFilters = make(map[string]nounPattern)
+		r.configExcludeFilters = make(map[string]nounPattern)
+		r.nounLevelOverrides = make(map[string]LogLevel)
+
+		r.adapters = map[string]LogAdapter{
+			TestAdapterName:              mla,
+			TestAdapterName + ".capture": cla,
+			TestAdapterName + ".log":     tla,
+		}
+
+		r.defaultAdapterName = TestAdapterName
+		r.configurationLoaded = true
+	})
+
+	restore = func() {
+		Restore(saved)
+	}
+
+	return cla, restore
+}
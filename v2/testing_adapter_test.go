@@ -0,0 +1,66 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestInstallTestLogging(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameInfo)
+	LoadConfiguration(tcp)
+
+	ClearAdapters()
+
+	original := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("original", original)
+
+	AddExcludeFilter("installTest")
+	defer RemoveExcludeFilter("installTest")
+
+	rtb := new(recordingTB)
+	cla, restore := InstallTestLogging(rtb, levelNameDebug)
+
+	l := NewLogger("installTest")
+	l.Debugf(nil, "Debug message")
+
+	if cla.Contains(LevelDebug, "Debug message") != true {
+		t.Fatalf("Message not captured.")
+	} else if len(rtb.logs) != 1 || rtb.logs[0] != "installTest: [DEBUG]  Debug message" {
+		t.Fatalf("Message not written to the test log: %v", rtb.logs)
+	} else if original.debugTriggered != false {
+		t.Fatalf("Message should not reach the original adapter.")
+	}
+
+	restore()
+
+	if GetDefaultAdapterName() != "original" {
+		t.Fatalf("Default adapter not restored: [%s]", GetDefaultAdapterName())
+	}
+
+	NewLogger("otherTest").Infof(nil, "Info message")
+
+	if original.infoTriggered != true {
+		t.Fatalf("Original adapter not restored.")
+	} else if cla.Len() != 1 {
+		t.Fatalf("Nothing more should be captured after restoring.")
+	}
+
+	l.Infof(nil, "Info message")
+
+	if original.lastContext.Noun() != "otherTest" {
+		t.Fatalf("Exclude filter not restored.")
+	}
+}
+
+func TestInstallTestLogging__TestingT(t *testing.T) {
+	cla, restore := InstallTestLogging(t, levelNameDebug)
+	defer restore()
+
+	NewLogger("installTest").Warningf(nil, "Warning message")
+
+	cla.AssertLogged(t, LevelWarning, "Warning message")
+}
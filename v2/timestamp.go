@@ -0,0 +1,72 @@
+package log
+
+import (
+	"time"
+)
+
+// Clock provides the time at which messages are logged.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// SystemClock is the default clock. It returns time.Now().
+type SystemClock struct {
+}
+
+// Now returns the current time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	// processStart is when the package was initialized. Elapsed times for the
+	// system clock are measured from this.
+	processStart = time.Now()
+)
+
+// SetClock sets the clock used to timestamp messages. Elapsed times are then
+// measured from the clock's current time. Passing nil restores the system
+// clock (and elapsed times are measured from the start of the process).
+func SetClock(clock Clock) {
+	updateRegistry(func(r *registry) {
+		if clock == nil {
+			r.clock = SystemClock{}
+			r.clockStart = processStart
+		} else {
+			r.clock = clock
+			r.clockStart = clock.Now()
+		}
+	})
+}
+
+// Timestamp is the time at which a message was logged. It can be used
+// directly in the format template (e.g. `{{.Time.Format "15:04:05"}}`) and has
+// helpers for other common presentations.
+type Timestamp struct {
+	time.Time
+
+	start time.Time
+}
+
+// RFC3339Nano returns the time formatted as RFC3339 with nanoseconds.
+func (ts Timestamp) RFC3339Nano() string {
+	return ts.Time.Format(time.RFC3339Nano)
+}
+
+// FormatUTC returns the time in UTC formatted with the given layout.
+func (ts Timestamp) FormatUTC(layout string) string {
+	return ts.Time.UTC().Format(layout)
+}
+
+// FormatLocal returns the time in the local timezone formatted with the given
+// layout.
+func (ts Timestamp) FormatLocal(layout string) string {
+	return ts.Time.Local().Format(layout)
+}
+
+// Elapsed returns the time since the process started (or since the clock was
+// set). This uses the monotonic clock when available.
+func (ts Timestamp) Elapsed() time.Duration {
+	return ts.Time.Sub(ts.start)
+}
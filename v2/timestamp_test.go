@@ -0,0 +1,106 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (tc *testClock) Now() time.Time {
+	return tc.now
+}
+
+func TestTimestamp(t *testing.T) {
+	start := time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("test", 3600))
+
+	ts := Timestamp{
+		Time:  start.Add(90 * time.Second),
+		start: start,
+	}
+
+	if ts.RFC3339Nano() != "2020-01-02T03:05:35.000000006+01:00" {
+		t.Fatalf("RFC3339Nano not correct: [%s]", ts.RFC3339Nano())
+	} else if ts.FormatUTC("15:04:05") != "02:05:35" {
+		t.Fatalf("FormatUTC not correct: [%s]", ts.FormatUTC("15:04:05"))
+	} else if ts.FormatLocal(time.RFC3339) != ts.Time.Local().Format(time.RFC3339) {
+		t.Fatalf("FormatLocal not correct: [%s]", ts.FormatLocal(time.RFC3339))
+	} else if ts.Elapsed() != 90*time.Second {
+		t.Fatalf("Elapsed not correct: [%s]", ts.Elapsed())
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tc := &testClock{
+		now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	SetClock(tc)
+
+	scp := NewStaticConfigurationProvider()
+	scp.SetLevelName(levelNameDebug)
+	scp.SetFormat(`{{.Time.Format "15:04:05"}} +{{.Time.Elapsed}} {{.Message}}`)
+
+	LoadConfiguration(scp)
+
+	ClearAdapters()
+
+	tla := newTestLogAdapter().(*testLogAdapter)
+	AddAdapter("test", tla)
+
+	l := NewLoggerWithAdapterName("timeTest", "test")
+
+	tc.now = tc.now.Add(2 * time.Second)
+	l.Infof(nil, "Info message")
+
+	if tla.lastMessage != "03:04:07 +2s Info message" {
+		t.Fatalf("Message not correct: [%s]", tla.lastMessage)
+	} else if tla.lastContext.Time().Equal(tc.now) != true {
+		t.Fatalf("Context time not correct: [%s]", tla.lastContext.Time())
+	}
+
+	SetClock(nil)
+
+	l.Infof(nil, "Info message")
+
+	if _, ok := loadRegistry().clock.(SystemClock); ok != true {
+		t.Fatalf("System clock not restored.")
+	} else if tla.lastContext.Time().Elapsed() <= 0 {
+		t.Fatalf("Elapsed time should be measured from the start of the process.")
+	}
+}
+
+func TestJSONLogAdapter__Clock(t *testing.T) {
+	cs := Snapshot()
+	defer func() {
+		Restore(cs)
+	}()
+
+	tcp := newTestConfigurationProvider(levelNameDebug)
+	LoadConfiguration(tcp)
+
+	SetClock(&testClock{
+		now: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC),
+	})
+
+	ClearAdapters()
+
+	b := new(bytes.Buffer)
+	AddAdapter("json", NewJSONLogAdapter(b))
+
+	l := NewLoggerWithAdapterName("timeTest", "json")
+	l.Infof(nil, "Info message")
+
+	if strings.HasPrefix(b.String(), `{"time":"2020-01-02T03:04:05.000000006Z",`) != true {
+		t.Fatalf("Time not taken from the clock: [%s]", b.String())
+	}
+}